@@ -15,8 +15,11 @@ resource "jumpcloud_user_group_membership" "test_membership" {
 # Setup: System groups
 resource "jumpcloud_user_group" "test_group" {
   name = "test_group"
-  attributes = {
-    posix_groups = "32:testerino"
+  attributes {
+    posix_group {
+      id   = 32
+      name = "testerino"
+    }
   }
 }
 
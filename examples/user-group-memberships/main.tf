@@ -15,6 +15,40 @@ resource "jumpcloud_user_group_memberships" "developer" {
   ]
 }
 
+# Shared users - only manage the groups owned by this configuration
+#
+# In managed_only mode, memberships granted elsewhere (other modules, the
+# JumpCloud console) are ignored, and destroying the resource only removes the
+# user from the groups listed here.
+resource "jumpcloud_user_group_memberships" "contractor" {
+  user_email = "contractor@example.com"
+  mode       = "managed_only"
+
+  groups = [
+    "aws-web_staging-developer",
+  ]
+}
+
+# Referencing groups by ID, e.g. jumpcloud_user_group.example.id,
+# and granting admin rights on system groups
+#
+# Names must match exactly one group, case-sensitively; ambiguous names fail
+# and have to be replaced by IDs. Only the listed system groups are managed.
+#
+# Group IDs are passed in group_id_set. group_ids is output only: it's the
+# computed map of group names to IDs and cannot be set.
+resource "jumpcloud_user_group_memberships" "sre" {
+  user_email = "sre@example.com"
+
+  group_id_set = [
+    "5f1b1c2d3e4f5a6b7c8d9e0f",
+  ]
+
+  system_groups = [
+    "5f1b1c2d3e4f5a6b7c8d9e10",
+  ]
+}
+
 # Example using for_each with a YAML file (recommended for managing many users)
 #
 # Given a jumpcloud_users.yaml file like:
@@ -37,7 +71,7 @@ resource "jumpcloud_user_group_memberships" "users" {
   groups     = each.value.groups
 }
 
-# Output the computed user ID and group ID mappings
+# Output the computed user ID and group ID mappings (group_ids is output only)
 output "developer_user_id" {
   value = jumpcloud_user_group_memberships.developer.user_id
 }
@@ -1,8 +1,11 @@
 # User Group with POSIX attributes
 resource "jumpcloud_user_group" "test_group" {
   name = "test_group"
-  attributes = {
-    posix_groups = "32:testerino"
+  attributes {
+    posix_group {
+      id   = 32
+      name = "testerino"
+    }
   }
 }
 
@@ -0,0 +1,111 @@
+package jumpcloud
+
+import (
+	"fmt"
+
+	jcapiv2 "github.com/TheJumpCloud/jcapi-go/v2"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceJumpCloudApplicationUsers() *schema.Resource {
+	return &schema.Resource{
+		Description: "Use this data source to list the JumpCloud users that can access an application, directly or through groups.",
+		Read: readObjectUsers("application_id", func(client *jcapiv2.APIClient) graphTraversal {
+			return client.ApplicationsApi.GraphApplicationTraverseUser
+		}),
+		Schema: objectUsersSchema("application_id", "The ID of the application, e.g. from the `jumpcloud_application` data source"),
+	}
+}
+
+// objectUsersSchema returns the schema of a data source listing the users
+// that can access the object identified by idKey
+func objectUsersSchema(idKey, idDescription string) map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		idKey: {
+			Type:        schema.TypeString,
+			Required:    true,
+			Description: idDescription,
+		},
+		"users": {
+			Type:     schema.TypeList,
+			Computed: true,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"id": {
+						Type:     schema.TypeString,
+						Computed: true,
+					},
+					"email": {
+						Type:     schema.TypeString,
+						Computed: true,
+					},
+					"username": {
+						Type:     schema.TypeString,
+						Computed: true,
+					},
+					"direct": {
+						Type:        schema.TypeBool,
+						Computed:    true,
+						Description: "Whether the user is bound to the object directly",
+					},
+					"paths": {
+						Type:        schema.TypeList,
+						Computed:    true,
+						Description: "The paths through the graph that grant the access",
+						Elem: &schema.Resource{
+							Schema: map[string]*schema.Schema{
+								"group_ids": {
+									Type:        schema.TypeList,
+									Computed:    true,
+									Description: "The IDs of the groups along the path, starting from the object",
+									Elem: &schema.Schema{
+										Type: schema.TypeString,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// readObjectUsers returns the read function of a data source listing the
+// users the given traversal reaches from the object identified by idKey
+func readObjectUsers(idKey string, traversal func(client *jcapiv2.APIClient) graphTraversal) schema.ReadFunc {
+	return func(d *schema.ResourceData, m interface{}) error {
+		config := m.(*jcapiv2.Configuration)
+		client := jcapiv2.NewAPIClient(config)
+		objectID := d.Get(idKey).(string)
+
+		objects, err := traverseGraph(traversal(client), objectID)
+		if err != nil {
+			return fmt.Errorf("error listing users of %s: %s", objectID, err)
+		}
+
+		userIDs := make([]string, 0, len(objects))
+		for _, object := range objects {
+			userIDs = append(userIDs, object.Id)
+		}
+		details, err := userIDsToDetails(config, userIDs)
+		if err != nil {
+			return err
+		}
+
+		users := make([]interface{}, 0, len(objects))
+		for _, access := range flattenEffectiveAccess("user", objects) {
+			user := access.(map[string]interface{})
+			delete(user, "type")
+			user["email"] = details[user["id"].(string)].Email
+			user["username"] = details[user["id"].(string)].Username
+			users = append(users, user)
+		}
+
+		if err := d.Set("users", users); err != nil {
+			return err
+		}
+		d.SetId(objectID)
+		return nil
+	}
+}
@@ -0,0 +1,81 @@
+package jumpcloud
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestDataSourceApplicationUsersBasic(t *testing.T) {
+	rName := acctest.RandStringFromCharSet(10, acctest.CharSetAlpha)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testDataSourceApplicationUsersConfig(rName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.jumpcloud_application_users.test_users", "users.#", "2"),
+					resource.TestCheckTypeSetElemNestedAttrs("data.jumpcloud_application_users.test_users", "users.*", map[string]string{
+						"username": rName + "_direct",
+						"email":    rName + "_direct@testorg.com",
+						"direct":   "true",
+					}),
+					resource.TestCheckTypeSetElemNestedAttrs("data.jumpcloud_application_users.test_users", "users.*", map[string]string{
+						"username": rName + "_group",
+						"direct":   "false",
+					}),
+				),
+			},
+		},
+	})
+}
+
+func testDataSourceApplicationUsersConfig(name string) string {
+	return fmt.Sprintf(`
+		resource "jumpcloud_application" "test_app" {
+			display_name = "test_app_%[1]s"
+			sso_url      = "https://sso.jumpcloud.com/saml2/test_app_%[1]s"
+		}
+
+		resource "jumpcloud_user_group" "test_group" {
+			name = "test_group_%[1]s"
+		}
+
+		resource "jumpcloud_user_group_association" "test_group_assoc" {
+			group_id  = jumpcloud_user_group.test_group.id
+			object_id = jumpcloud_application.test_app.id
+			type      = "application"
+		}
+
+		resource "jumpcloud_user" "test_group_user" {
+			username = "%[1]s_group"
+			email    = "%[1]s_group@testorg.com"
+			groups   = [jumpcloud_user_group.test_group.id]
+		}
+
+		resource "jumpcloud_user" "test_direct_user" {
+			username = "%[1]s_direct"
+			email    = "%[1]s_direct@testorg.com"
+		}
+
+		resource "jumpcloud_user_association" "test_user_assoc" {
+			user_id   = jumpcloud_user.test_direct_user.id
+			object_id = jumpcloud_application.test_app.id
+			type      = "application"
+		}
+
+		data "jumpcloud_application_users" "test_users" {
+			application_id = jumpcloud_application.test_app.id
+
+			depends_on = [
+				jumpcloud_user_group_association.test_group_assoc,
+				jumpcloud_user_association.test_user_assoc,
+				jumpcloud_user.test_group_user,
+			]
+		}
+	`, name)
+}
@@ -0,0 +1,16 @@
+package jumpcloud
+
+import (
+	jcapiv2 "github.com/TheJumpCloud/jcapi-go/v2"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceJumpCloudSystemUsers() *schema.Resource {
+	return &schema.Resource{
+		Description: "Use this data source to list the JumpCloud users that can log into a system, directly or through groups.",
+		Read: readObjectUsers("system_id", func(client *jcapiv2.APIClient) graphTraversal {
+			return client.SystemsApi.GraphSystemTraverseUser
+		}),
+		Schema: objectUsersSchema("system_id", "The ID of the system"),
+	}
+}
@@ -2,41 +2,180 @@ package jumpcloud
 
 import (
 	"context"
-	"encoding/json"
-	"errors"
 	"fmt"
+	"strings"
 
 	jcapiv1 "github.com/TheJumpCloud/jcapi-go/v1"
 	jcapiv2 "github.com/TheJumpCloud/jcapi-go/v2"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	// "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
+// userLookupFields maps the attributes a user can be looked up by to the
+// JumpCloud fields they are matched against
+var userLookupFields = map[string]string{
+	"email":               "email",
+	"username":            "username",
+	"employee_identifier": "employeeIdentifier",
+}
+
 func dataSourceJumpCloudUser() *schema.Resource {
+	lookupKeys := []string{"id", "email", "username", "employee_identifier"}
+
 	return &schema.Resource{
-		Read: dataSourceJumpCloudUserRead,
+		Description: "Use this data source to get information about a JumpCloud user.",
+		Read:        dataSourceJumpCloudUserRead,
 		Schema: map[string]*schema.Schema{
+			"id": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ExactlyOneOf: lookupKeys,
+				Description:  "The ID of the user to look up",
+			},
 			"email": {
-				Type:     schema.TypeString,
-				Required: true,
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ExactlyOneOf: lookupKeys,
+				Description:  "The e-mail address of the user to look up",
 			},
 			"username": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ExactlyOneOf: lookupKeys,
+				Description:  "The username of the user to look up",
+			},
+			"employee_identifier": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ExactlyOneOf: lookupKeys,
+				Description:  "The employee identifier of the user to look up",
+			},
+			"firstname": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"lastname": {
 				Type:     schema.TypeString,
 				Computed: true,
 			},
+			"display_name": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"enable_mfa": {
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+			"ldap_binding_user": {
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+			"passwordless_sudo": {
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+			"password_never_expires": {
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+			"sudo": {
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+			"suspended": {
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+			"phone_number": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"number": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"type": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+			"state": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The state of the user, e.g. STAGED, ACTIVATED or SUSPENDED",
+			},
+			"activated": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether the user has activated its account",
+			},
+			"account_locked": {
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+			"mfa_configured": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether the user has configured an MFA method",
+			},
+			"totp_enabled": {
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+			"groups": {
+				Type:        schema.TypeSet,
+				Computed:    true,
+				Description: "Set of group IDs this user belongs to",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"group_names": {
+				Type:        schema.TypeSet,
+				Computed:    true,
+				Description: "Set of group names this user belongs to",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"ldap_servers": {
+				Type:        schema.TypeSet,
+				Computed:    true,
+				Description: "Set of LDAP server IDs the user is directly bound to",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"externally_managed": {
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+			"external_source_type": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"restricted_fields": {
+				Type:     schema.TypeSet,
+				Computed: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
 		},
 	}
 }
 
-func getUserDetails(client *jcapiv1.APIClient, email string) (*jcapiv1.Systemuserreturn, error) {
-	ctx := context.TODO()
-
-	contentType := "application/json"
-	accept := "application/json"
-
-	filterJson := fmt.Sprintf("[{\"email\": \"%s\"}]", email)
-	var filter interface{}
-	json.Unmarshal([]byte(filterJson), &filter)
+// findUser returns the only user whose field matches value exactly
+func findUser(client *jcapiv1.APIClient, field, value string) (*jcapiv1.Systemuserreturn, error) {
+	var filter interface{} = []interface{}{
+		map[string]interface{}{field: value},
+	}
 
 	optionals := map[string]interface{}{
 		"body": jcapiv1.Search{
@@ -44,38 +183,128 @@ func getUserDetails(client *jcapiv1.APIClient, email string) (*jcapiv1.Systemuse
 		},
 	}
 
-	res, _, err := client.SearchApi.SearchSystemusersPost(ctx, contentType, accept, optionals)
+	res, _, err := client.SearchApi.SearchSystemusersPost(context.TODO(), headerAccept, headerAccept, optionals)
 	if err != nil {
 		return nil, err
 	}
 
-	// Check if user is found
-	if len(res.Results) == 0 {
-		return nil, fmt.Errorf("no user found with the given email: %s", email)
+	var matches []jcapiv1.Systemuserreturn
+	for _, user := range res.Results {
+		if userFieldMatches(user, field, value) {
+			matches = append(matches, user)
+		}
 	}
 
-	// Return the first user found
-	user := res.Results[0]
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("no user found with the given %s: %s", field, value)
+	case 1:
+		return &matches[0], nil
+	default:
+		return nil, fmt.Errorf("%d users found with the given %s: %s", len(matches), field, value)
+	}
+}
+
+// userFieldMatches compares e-mail addresses case-insensitively, all other
+// fields exactly
+func userFieldMatches(user jcapiv1.Systemuserreturn, field, value string) bool {
+	switch field {
+	case "email":
+		return strings.EqualFold(user.Email, value)
+	case "username":
+		return user.Username == value
+	case "employeeIdentifier":
+		return user.EmployeeIdentifier == value
+	}
+	return false
+}
 
-	return &user, nil
+func getUserDetails(client *jcapiv1.APIClient, email string) (*jcapiv1.Systemuserreturn, error) {
+	return findUser(client, "email", email)
 }
 
 func dataSourceJumpCloudUserRead(d *schema.ResourceData, m interface{}) error {
-	configv1 := convertV2toV1Config(m.(*jcapiv2.Configuration))
-	client := jcapiv1.NewAPIClient(configv1)
-	userEmail := d.Get("email").(string)
+	config := m.(*jcapiv2.Configuration)
+	clientv1 := jcapiv1.NewAPIClient(convertV2toV1Config(config))
+	clientv2 := jcapiv2.NewAPIClient(config)
 
-	// Use the getUserDetails function to query user details using the userEmail
-	user, err := getUserDetails(client, userEmail)
+	userID := d.Get("id").(string)
+	if userID == "" {
+		for key, field := range userLookupFields {
+			value, ok := d.GetOk(key)
+			if !ok {
+				continue
+			}
+			found, err := findUser(clientv1, field, value.(string))
+			if err != nil {
+				return err
+			}
+			userID = found.Id
+			break
+		}
+	}
 
-	// If an error occurs or no user is found, return an error
+	user, ok, err := userReadHelper(config, userID)
 	if err != nil {
-		return errors.New("user not found")
+		return err
+	}
+	if !ok || user == nil || user.Id == "" {
+		return fmt.Errorf("no user found with the given id: %s", userID)
 	}
 
-	// Set the user ID in the Terraform resource data object
 	d.SetId(user.Id)
-	d.Set("id", user.Id)
 
-	return nil
+	values := map[string]interface{}{
+		"email":                  user.Email,
+		"username":               user.Username,
+		"employee_identifier":    user.EmployeeIdentifier,
+		"firstname":              user.Firstname,
+		"lastname":               user.Lastname,
+		"display_name":           user.Displayname,
+		"enable_mfa":             user.EnableUserPortalMultifactor,
+		"ldap_binding_user":      user.LdapBindingUser,
+		"passwordless_sudo":      user.PasswordlessSudo,
+		"password_never_expires": user.PasswordNeverExpires,
+		"sudo":                   user.Sudo,
+		"suspended":              user.Suspended,
+		"phone_number":           flattenPhoneNumbers(user.PhoneNumbers),
+		"state":                  user.State,
+		"activated":              user.Activated,
+		"account_locked":         user.AccountLocked,
+		"mfa_configured":         user.Mfa != nil && user.Mfa.Configured,
+		"totp_enabled":           user.TotpEnabled,
+		"externally_managed":     user.ExternallyManaged,
+		"external_source_type":   user.ExternalSourceType,
+		"restricted_fields":      flattenRestrictedFields(user.RestrictedFields),
+	}
+	for key, value := range values {
+		if err := d.Set(key, value); err != nil {
+			return err
+		}
+	}
+
+	groupIDs, err := getUserGroupIDs(clientv2, user.Id)
+	if err != nil {
+		return err
+	}
+	if err := d.Set("groups", groupIDs); err != nil {
+		return err
+	}
+	groupNames, err := getGroupIDToNameMap(clientv2, groupIDs)
+	if err != nil {
+		return err
+	}
+	names := make([]string, 0, len(groupNames))
+	for _, name := range groupNames {
+		names = append(names, name)
+	}
+	if err := d.Set("group_names", names); err != nil {
+		return err
+	}
+
+	ldapServers, err := readUserLdapServers(config, user.Id)
+	if err != nil {
+		return err
+	}
+	return d.Set("ldap_servers", ldapServers)
 }
@@ -0,0 +1,163 @@
+package jumpcloud
+
+import (
+	"fmt"
+	"sort"
+
+	jcapiv2 "github.com/TheJumpCloud/jcapi-go/v2"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// userTraversals returns the graph traversals from a user, keyed by the type
+// of the objects they reach
+func userTraversals(client *jcapiv2.APIClient) map[string]graphTraversal {
+	return map[string]graphTraversal{
+		"system":        client.UsersApi.GraphUserTraverseSystem,
+		"system_group":  client.UsersApi.GraphUserTraverseSystemGroup,
+		"application":   client.UsersApi.GraphUserTraverseApplication,
+		"radius_server": client.UsersApi.GraphUserTraverseRadiusServer,
+		"ldap_server":   client.UsersApi.GraphUserTraverseLdapServer,
+		"g_suite":       client.UsersApi.GraphUserTraverseGSuite,
+		"office_365":    client.UsersApi.GraphUserTraverseOffice365,
+	}
+}
+
+var effectiveAccessTypes = []string{
+	"application",
+	"g_suite",
+	"ldap_server",
+	"office_365",
+	"radius_server",
+	"system",
+	"system_group",
+}
+
+func dataSourceJumpCloudUserEffectiveAccess() *schema.Resource {
+	return &schema.Resource{
+		Description: "Use this data source to list the objects a JumpCloud user can access, directly or through groups.",
+		Read:        dataSourceJumpCloudUserEffectiveAccessRead,
+		Schema: map[string]*schema.Schema{
+			"user_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The ID of the user",
+			},
+			"types": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Description: "The types of objects to list; all types if unset",
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.StringInSlice(effectiveAccessTypes, false),
+				},
+			},
+			"access": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"type": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"direct": {
+							Type:        schema.TypeBool,
+							Computed:    true,
+							Description: "Whether the user is bound to the object directly",
+						},
+						"paths": {
+							Type:        schema.TypeList,
+							Computed:    true,
+							Description: "The paths through the graph that grant the access",
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"group_ids": {
+										Type:        schema.TypeList,
+										Computed:    true,
+										Description: "The IDs of the groups along the path, starting from the user",
+										Elem: &schema.Schema{
+											Type: schema.TypeString,
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// pathGroupIDs returns the IDs of the objects between the user and the
+// target of a path, i.e. the groups granting the access
+func pathGroupIDs(path []jcapiv2.GraphConnection) []string {
+	groupIDs := []string{}
+	for i, conn := range path {
+		if i == len(path)-1 {
+			break
+		}
+		if conn.To != nil && conn.To.Id != "" {
+			groupIDs = append(groupIDs, conn.To.Id)
+		}
+	}
+	return groupIDs
+}
+
+// flattenEffectiveAccess converts traversed objects of the given type to the
+// access attribute
+func flattenEffectiveAccess(objectType string, objects []jcapiv2.GraphObjectWithPaths) []interface{} {
+	access := make([]interface{}, 0, len(objects))
+	for _, object := range objects {
+		direct := false
+		paths := make([]interface{}, 0, len(object.Paths))
+		for _, path := range object.Paths {
+			groupIDs := pathGroupIDs(path)
+			if len(groupIDs) == 0 {
+				direct = true
+			}
+			paths = append(paths, map[string]interface{}{
+				"group_ids": groupIDs,
+			})
+		}
+		access = append(access, map[string]interface{}{
+			"id":     object.Id,
+			"type":   objectType,
+			"direct": direct,
+			"paths":  paths,
+		})
+	}
+	return access
+}
+
+func dataSourceJumpCloudUserEffectiveAccessRead(d *schema.ResourceData, m interface{}) error {
+	client := jcapiv2.NewAPIClient(m.(*jcapiv2.Configuration))
+	userID := d.Get("user_id").(string)
+
+	types := effectiveAccessTypes
+	if configured := d.Get("types").(*schema.Set); configured.Len() > 0 {
+		types = expandStringSet(configured)
+		sort.Strings(types)
+	}
+
+	traversals := userTraversals(client)
+	access := []interface{}{}
+	for _, objectType := range types {
+		objects, err := traverseGraph(traversals[objectType], userID)
+		if err != nil {
+			return fmt.Errorf("error listing %s access of user %s: %s", objectType, userID, err)
+		}
+		access = append(access, flattenEffectiveAccess(objectType, objects)...)
+	}
+
+	if err := d.Set("access", access); err != nil {
+		return err
+	}
+	d.SetId(userID)
+	return nil
+}
@@ -0,0 +1,140 @@
+package jumpcloud
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+
+	jcapiv2 "github.com/TheJumpCloud/jcapi-go/v2"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestTraverseGraphPages(t *testing.T) {
+	var skips []int32
+	traverse := func(_ context.Context, _ string, _ string, _ string,
+		optionals map[string]interface{}) ([]jcapiv2.GraphObjectWithPaths, *http.Response, error) {
+		skip := optionals["skip"].(int32)
+		skips = append(skips, skip)
+		size := 100
+		if skip >= 100 {
+			size = 3
+		}
+		return make([]jcapiv2.GraphObjectWithPaths, size), nil, nil
+	}
+
+	objects, err := traverseGraph(traverse, "user")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(objects) != 103 {
+		t.Errorf("Expected 103 objects, got %d", len(objects))
+	}
+	if !reflect.DeepEqual(skips, []int32{0, 100}) {
+		t.Errorf("Expected two pages, got skips %v", skips)
+	}
+}
+
+func TestFlattenEffectiveAccess(t *testing.T) {
+	objects := []jcapiv2.GraphObjectWithPaths{
+		{
+			Id: "system1",
+			Paths: [][]jcapiv2.GraphConnection{
+				{{To: &jcapiv2.GraphObject{Id: "system1", Type_: "system"}}},
+				{
+					{To: &jcapiv2.GraphObject{Id: "group1", Type_: "user_group"}},
+					{To: &jcapiv2.GraphObject{Id: "sysgroup1", Type_: "system_group"}},
+					{To: &jcapiv2.GraphObject{Id: "system1", Type_: "system"}},
+				},
+			},
+		},
+		{
+			Id: "system2",
+			Paths: [][]jcapiv2.GraphConnection{
+				{
+					{To: &jcapiv2.GraphObject{Id: "group2", Type_: "user_group"}},
+					{To: &jcapiv2.GraphObject{Id: "system2", Type_: "system"}},
+				},
+			},
+		},
+	}
+
+	access := flattenEffectiveAccess("system", objects)
+	expected := []interface{}{
+		map[string]interface{}{
+			"id":     "system1",
+			"type":   "system",
+			"direct": true,
+			"paths": []interface{}{
+				map[string]interface{}{"group_ids": []string{}},
+				map[string]interface{}{"group_ids": []string{"group1", "sysgroup1"}},
+			},
+		},
+		map[string]interface{}{
+			"id":     "system2",
+			"type":   "system",
+			"direct": false,
+			"paths": []interface{}{
+				map[string]interface{}{"group_ids": []string{"group2"}},
+			},
+		},
+	}
+	if !reflect.DeepEqual(access, expected) {
+		t.Errorf("Expected %v, got %v", expected, access)
+	}
+}
+
+func TestDataSourceUserEffectiveAccessBasic(t *testing.T) {
+	rName := acctest.RandStringFromCharSet(10, acctest.CharSetAlpha)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testDataSourceUserEffectiveAccessConfig(rName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.jumpcloud_user_effective_access.test_access", "access.#", "1"),
+					resource.TestCheckResourceAttr("data.jumpcloud_user_effective_access.test_access", "access.0.type", "application"),
+					resource.TestCheckResourceAttr("data.jumpcloud_user_effective_access.test_access", "access.0.direct", "false"),
+					resource.TestCheckResourceAttrPair("data.jumpcloud_user_effective_access.test_access", "access.0.paths.0.group_ids.0",
+						"jumpcloud_user_group.test_group", "id"),
+				),
+			},
+		},
+	})
+}
+
+func testDataSourceUserEffectiveAccessConfig(name string) string {
+	return fmt.Sprintf(`
+		resource "jumpcloud_application" "test_app" {
+			display_name = "test_app_%[1]s"
+			sso_url      = "https://sso.jumpcloud.com/saml2/test_app_%[1]s"
+		}
+
+		resource "jumpcloud_user_group" "test_group" {
+			name = "test_group_%[1]s"
+		}
+
+		resource "jumpcloud_user_group_association" "test_assoc" {
+			group_id  = jumpcloud_user_group.test_group.id
+			object_id = jumpcloud_application.test_app.id
+			type      = "application"
+		}
+
+		resource "jumpcloud_user" "test_user" {
+			username = "%[1]s"
+			email    = "%[1]s@testorg.com"
+			groups   = [jumpcloud_user_group.test_group.id]
+		}
+
+		data "jumpcloud_user_effective_access" "test_access" {
+			user_id = jumpcloud_user.test_user.id
+			types   = ["application"]
+
+			depends_on = [jumpcloud_user_group_association.test_assoc]
+		}
+	`, name)
+}
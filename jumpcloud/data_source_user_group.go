@@ -20,6 +20,14 @@ func dataSourceJumpCloudUserGroup() *schema.Resource {
 				Type:     schema.TypeString,
 				Computed: true,
 			},
+			"description": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"email": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
 			"members": {
 				Type:        schema.TypeList,
 				Computed:    true,
@@ -55,15 +63,29 @@ func dataSourceJumpCloudUserGroupRead(d *schema.ResourceData, m interface{}) err
 		if group.Name == groupName {
 			d.SetId(group.Id)
 
+			// The SDK's UserGroup lacks the description and email
+			details, ok, err := userGroupReadHelper(config, d.Id())
+			if err != nil {
+				return err
+			}
+			if ok && details != nil {
+				if err := d.Set("description", details.Description); err != nil {
+					return err
+				}
+				if err := d.Set("email", details.Email); err != nil {
+					return err
+				}
+			}
+
 			memberIDs, err := getUserGroupMemberIDs(client, d.Id())
 			if err != nil {
 				return err
 			}
-			memberEmails, err := userIDsToEmails(config, memberIDs)
+			emails, err := memberEmails(config, memberIDs)
 			if err != nil {
 				return err
 			}
-			if err := d.Set("members", memberEmails); err != nil {
+			if err := d.Set("members", emails); err != nil {
 				return err
 			}
 			return nil
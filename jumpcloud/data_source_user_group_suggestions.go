@@ -0,0 +1,124 @@
+package jumpcloud
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+
+	jcapiv2 "github.com/TheJumpCloud/jcapi-go/v2"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceJumpCloudUserGroupSuggestions() *schema.Resource {
+	suggestionSchema := &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"user_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"email": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+
+	return &schema.Resource{
+		Description: "Use this data source to list the pending member suggestions of a JumpCloud user group with membership_method dynamic_review.",
+		Read:        dataSourceJumpCloudUserGroupSuggestionsRead,
+		Schema: map[string]*schema.Schema{
+			"group_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The ID of the user group",
+			},
+			"additions": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The users suggested to be added to the group",
+				Elem:        suggestionSchema,
+			},
+			"removals": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The users suggested to be removed from the group",
+				Elem:        suggestionSchema,
+			},
+		},
+	}
+}
+
+// listUserGroupSuggestions returns all pending member suggestions of a group
+func listUserGroupSuggestions(config *jcapiv2.Configuration, groupID string) ([]UserGroupSuggestion, error) {
+	var suggestions []UserGroupSuggestion
+	err := forEachPage(func(optionals map[string]interface{}) (int, error) {
+		query := url.Values{}
+		query.Set("limit", fmt.Sprint(optionals["limit"]))
+		query.Set("skip", fmt.Sprint(optionals["skip"]))
+
+		var page []UserGroupSuggestion
+		if err := doAPIRequest(config, http.MethodGet,
+			config.BasePath+"/usergroups/"+groupID+"/suggestions?"+query.Encode(), nil, &page); err != nil {
+			return 0, err
+		}
+		suggestions = append(suggestions, page...)
+		return len(page), nil
+	})
+	return suggestions, err
+}
+
+// splitUserGroupSuggestions returns the IDs of the users suggested for
+// addition and removal, sorted
+func splitUserGroupSuggestions(suggestions []UserGroupSuggestion) (additions, removals []string) {
+	additions, removals = []string{}, []string{}
+	for _, suggestion := range suggestions {
+		if suggestion.Object == nil || suggestion.Object.Id == "" {
+			continue
+		}
+		switch suggestion.Op {
+		case "add":
+			additions = append(additions, suggestion.Object.Id)
+		case "remove":
+			removals = append(removals, suggestion.Object.Id)
+		}
+	}
+	sort.Strings(additions)
+	sort.Strings(removals)
+	return additions, removals
+}
+
+func dataSourceJumpCloudUserGroupSuggestionsRead(d *schema.ResourceData, m interface{}) error {
+	config := m.(*jcapiv2.Configuration)
+	groupID := d.Get("group_id").(string)
+
+	suggestions, err := listUserGroupSuggestions(config, groupID)
+	if err != nil {
+		return fmt.Errorf("error listing suggestions of user group %s: %s", groupID, err)
+	}
+	additions, removals := splitUserGroupSuggestions(suggestions)
+
+	details, err := userIDsToDetails(config, append(append([]string{}, additions...), removals...))
+	if err != nil {
+		return err
+	}
+	flatten := func(userIDs []string) []interface{} {
+		users := make([]interface{}, 0, len(userIDs))
+		for _, id := range userIDs {
+			users = append(users, map[string]interface{}{
+				"user_id": id,
+				"email":   details[id].Email,
+			})
+		}
+		return users
+	}
+
+	if err := d.Set("additions", flatten(additions)); err != nil {
+		return err
+	}
+	if err := d.Set("removals", flatten(removals)); err != nil {
+		return err
+	}
+	d.SetId(groupID)
+	return nil
+}
@@ -0,0 +1,80 @@
+package jumpcloud
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	jcapiv2 "github.com/TheJumpCloud/jcapi-go/v2"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestDataSourceUserGroupSuggestionsBasic(t *testing.T) {
+	rName := acctest.RandStringFromCharSet(10, acctest.CharSetAlpha)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testDataSourceUserGroupSuggestionsConfig(rName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair("data.jumpcloud_user_group_suggestions.test_suggestions", "id",
+						"jumpcloud_user_group.test_group", "id"),
+					resource.TestCheckResourceAttrSet("data.jumpcloud_user_group_suggestions.test_suggestions", "additions.#"),
+					resource.TestCheckResourceAttr("data.jumpcloud_user_group_suggestions.test_suggestions", "removals.#", "0"),
+				),
+			},
+		},
+	})
+}
+
+func testDataSourceUserGroupSuggestionsConfig(name string) string {
+	return fmt.Sprintf(`
+		resource "jumpcloud_user" "test_user" {
+			username   = "%[1]s"
+			email      = "%[1]s@testorg.com"
+			department = "%[1]s"
+		}
+
+		resource "jumpcloud_user_group" "test_group" {
+			name              = "test_group_%[1]s"
+			membership_method = "dynamic_review"
+
+			member_query {
+				filter {
+					field    = "department"
+					operator = "eq"
+					value    = "%[1]s"
+				}
+			}
+
+			depends_on = [jumpcloud_user.test_user]
+		}
+
+		data "jumpcloud_user_group_suggestions" "test_suggestions" {
+			group_id = jumpcloud_user_group.test_group.id
+		}`, name,
+	)
+}
+
+func TestSplitUserGroupSuggestions(t *testing.T) {
+	additions, removals := splitUserGroupSuggestions([]UserGroupSuggestion{
+		{Object: &jcapiv2.GraphObject{Id: "b"}, Op: "add"},
+		{Object: &jcapiv2.GraphObject{Id: "c"}, Op: "remove"},
+		{Object: &jcapiv2.GraphObject{Id: "a"}, Op: "add"},
+		{Op: "add"},
+	})
+	if !reflect.DeepEqual(additions, []string{"a", "b"}) {
+		t.Errorf("Expected sorted additions, got %v", additions)
+	}
+	if !reflect.DeepEqual(removals, []string{"c"}) {
+		t.Errorf("Expected removals [c], got %v", removals)
+	}
+
+	additions, removals = splitUserGroupSuggestions(nil)
+	if additions == nil || removals == nil {
+		t.Error("Expected empty lists without suggestions")
+	}
+}
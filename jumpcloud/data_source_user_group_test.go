@@ -18,7 +18,9 @@ func TestDataSourceUserGroupBasic(t *testing.T) {
 			{
 				Config: testDataSourceUserGroupConfigBasic(rName),
 				Check: resource.ComposeTestCheckFunc(
-					resource.TestCheckResourceAttr("data.jumpcloud_user_group.test_group", "name", rName),
+					resource.TestCheckResourceAttr("data.jumpcloud_user_group.test_group", "group_name", rName),
+					resource.TestCheckResourceAttr("data.jumpcloud_user_group.test_group", "description", "Owned by "+rName),
+					resource.TestCheckResourceAttr("data.jumpcloud_user_group.test_group", "email", rName+"@testorg.com"),
 				),
 			},
 		},
@@ -28,11 +30,13 @@ func TestDataSourceUserGroupBasic(t *testing.T) {
 func testDataSourceUserGroupConfigBasic(name string) string {
 	return fmt.Sprintf(`
 		resource "jumpcloud_user_group" "test_group" {
-			name = "%s"
+			name        = "%[1]s"
+			description = "Owned by %[1]s"
+			email       = "%[1]s@testorg.com"
 		}
 
 		data "jumpcloud_user_group" "test_group" {
-			name = jumpcloud_user_group.test_group.name
+			group_name = jumpcloud_user_group.test_group.name
 		}
 	`, name)
 }
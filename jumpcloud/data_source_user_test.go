@@ -20,6 +20,11 @@ func TestDataSourceUserBasic(t *testing.T) {
 				Check: resource.ComposeTestCheckFunc(
 					resource.TestCheckResourceAttr("data.jumpcloud_user.test_user", "username", rName),
 					resource.TestCheckResourceAttr("data.jumpcloud_user.test_user", "email", rName+"@testorg.com"),
+					resource.TestCheckResourceAttr("data.jumpcloud_user.test_user", "firstname", "first"),
+					resource.TestCheckResourceAttr("data.jumpcloud_user.test_user", "group_names.#", "1"),
+					resource.TestCheckResourceAttrPair("data.jumpcloud_user.by_id", "username", "jumpcloud_user.test_user", "username"),
+					resource.TestCheckResourceAttrPair("data.jumpcloud_user.by_email", "id", "jumpcloud_user.test_user", "id"),
+					resource.TestCheckResourceAttrSet("data.jumpcloud_user.by_email", "state"),
 				),
 			},
 		},
@@ -28,13 +33,27 @@ func TestDataSourceUserBasic(t *testing.T) {
 
 func testDataSourceUserConfigBasic(name string) string {
 	return fmt.Sprintf(`
+		resource "jumpcloud_user_group" "test_group" {
+			name = "test_group_%[1]s"
+		}
+
 		resource "jumpcloud_user" "test_user" {
-			username = "%s"
-			email    = "%s@testorg.com"
+			username  = "%[1]s"
+			email     = "%[1]s@testorg.com"
+			firstname = "first"
+			groups    = [jumpcloud_user_group.test_group.id]
 		}
 
 		data "jumpcloud_user" "test_user" {
 			username = jumpcloud_user.test_user.username
 		}
-	`, name, name)
+
+		data "jumpcloud_user" "by_id" {
+			id = jumpcloud_user.test_user.id
+		}
+
+		data "jumpcloud_user" "by_email" {
+			email = jumpcloud_user.test_user.email
+		}
+	`, name)
 }
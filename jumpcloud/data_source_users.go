@@ -0,0 +1,342 @@
+package jumpcloud
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	jcapiv1 "github.com/TheJumpCloud/jcapi-go/v1"
+	jcapiv2 "github.com/TheJumpCloud/jcapi-go/v2"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// userFilterOperators maps the operators of a users filter to the JumpCloud
+// query operators; eq compares the value directly
+var userFilterOperators = map[string]string{
+	"eq":     "",
+	"ne":     "$ne",
+	"regex":  "$regex",
+	"in":     "$in",
+	"exists": "$exists",
+}
+
+// userSortFields maps the attributes users can be sorted by to their values
+var userSortFields = map[string]func(u jcapiv1.Systemuserreturn) string{
+	"username":            func(u jcapiv1.Systemuserreturn) string { return u.Username },
+	"email":               func(u jcapiv1.Systemuserreturn) string { return u.Email },
+	"firstname":           func(u jcapiv1.Systemuserreturn) string { return u.Firstname },
+	"lastname":            func(u jcapiv1.Systemuserreturn) string { return u.Lastname },
+	"display_name":        func(u jcapiv1.Systemuserreturn) string { return u.Displayname },
+	"department":          func(u jcapiv1.Systemuserreturn) string { return u.Department },
+	"employee_identifier": func(u jcapiv1.Systemuserreturn) string { return u.EmployeeIdentifier },
+	"created":             func(u jcapiv1.Systemuserreturn) string { return u.Created },
+}
+
+func dataSourceJumpCloudUsers() *schema.Resource {
+	sortKeys := make([]string, 0, len(userSortFields))
+	for key := range userSortFields {
+		sortKeys = append(sortKeys, key, "-"+key)
+	}
+	sort.Strings(sortKeys)
+	operators := make([]string, 0, len(userFilterOperators))
+	for op := range userFilterOperators {
+		operators = append(operators, op)
+	}
+	sort.Strings(operators)
+
+	return &schema.Resource{
+		Description: "Use this data source to search JumpCloud users.",
+		Read:        dataSourceJumpCloudUsersRead,
+		Schema: map[string]*schema.Schema{
+			"filter": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Description: "Filters the users have to match, combined according to filter_match",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"field": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "The JumpCloud field to filter on, e.g. department or email",
+						},
+						"operator": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Default:      "eq",
+							Description:  "One of eq, ne, regex, in or exists",
+							ValidateFunc: validation.StringInSlice(operators, false),
+						},
+						"value": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "The value to compare the field with. true and false are compared as booleans",
+						},
+						"values": {
+							Type:        schema.TypeList,
+							Optional:    true,
+							Description: "The values to compare the field with, for the in operator",
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+						},
+					},
+				},
+			},
+			"filter_match": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "and",
+				Description:  "Either and, to return users matching all filters, or or, to return users matching any filter",
+				ValidateFunc: validation.StringInSlice([]string{"and", "or"}, false),
+			},
+			"search": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Full-text search term matched against search_fields",
+			},
+			"search_fields": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Description: "The fields the search term is matched against",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"sort": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Description:  "The attribute to sort the users by, prefixed with - for descending order",
+				ValidateFunc: validation.StringInSlice(sortKeys, false),
+			},
+			"limit": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Description:  "The maximum number of users to return; all matching users if unset",
+				ValidateFunc: validation.IntAtLeast(1),
+			},
+			"users": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"email": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"username": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"firstname": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"lastname": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"display_name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"department": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"employee_identifier": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"activated": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+						"suspended": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+						"externally_managed": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// buildUserSearchFilter converts the filter blocks to a JumpCloud search filter
+func buildUserSearchFilter(filters []interface{}, match string) (interface{}, error) {
+	conditions := make([]interface{}, 0, len(filters))
+	for _, f := range filters {
+		filter := f.(map[string]interface{})
+		field := filter["field"].(string)
+		operator := filter["operator"].(string)
+
+		var value interface{}
+		switch operator {
+		case "in":
+			values, _ := filter["values"].([]interface{})
+			if len(values) == 0 {
+				return nil, fmt.Errorf("filter on %s: the in operator requires values", field)
+			}
+			value = values
+		case "exists":
+			value = filter["value"].(string) != "false"
+		default:
+			raw := filter["value"].(string)
+			switch raw {
+			case "true":
+				value = true
+			case "false":
+				value = false
+			default:
+				value = raw
+			}
+		}
+
+		if op := userFilterOperators[operator]; op != "" {
+			value = map[string]interface{}{op: value}
+		}
+		conditions = append(conditions, map[string]interface{}{field: value})
+	}
+
+	if len(conditions) == 0 {
+		return nil, nil
+	}
+	return map[string]interface{}{match: conditions}, nil
+}
+
+// sortUsers sorts users by the given attribute, descending if prefixed with -
+func sortUsers(users []jcapiv1.Systemuserreturn, by string) {
+	descending := strings.HasPrefix(by, "-")
+	value, ok := userSortFields[strings.TrimPrefix(by, "-")]
+	if !ok {
+		return
+	}
+	sort.SliceStable(users, func(i, j int) bool {
+		if descending {
+			return value(users[i]) > value(users[j])
+		}
+		return value(users[i]) < value(users[j])
+	})
+}
+
+// userSearcher returns one page of the users matching the search body
+type userSearcher func(body jcapiv1.Search, optionals map[string]interface{}) ([]jcapiv1.Systemuserreturn, error)
+
+// searchSystemusers returns the userSearcher consuming the JC's HTTP API
+// directly, as the SDK's SearchSystemusersPost can't sort the results
+func searchSystemusers(config *jcapiv2.Configuration) userSearcher {
+	configv1 := convertV2toV1Config(config)
+	return func(body jcapiv1.Search, optionals map[string]interface{}) ([]jcapiv1.Systemuserreturn, error) {
+		// A stable order keeps users from moving between pages
+		url := fmt.Sprintf("%s/search/systemusers?limit=%d&skip=%d&sort=_id",
+			configv1.BasePath, optionals["limit"], optionals["skip"])
+
+		var page jcapiv1.Systemuserslist
+		if err := doAPIRequest(config, http.MethodPost, url, body, &page); err != nil {
+			return nil, err
+		}
+		return page.Results, nil
+	}
+}
+
+// searchUsers pages through all users matching the search body. Unless the
+// results have to be sorted first, it stops once limit users are found.
+// Users returned twice, e.g. as they changed while paging, are only kept once.
+func searchUsers(search userSearcher, body jcapiv1.Search, limit int, sorted bool) ([]jcapiv1.Systemuserreturn, error) {
+	var users []jcapiv1.Systemuserreturn
+	seen := make(map[string]bool)
+	err := forEachPage(func(optionals map[string]interface{}) (int, error) {
+		page, err := search(body, optionals)
+		if err != nil {
+			return 0, err
+		}
+		for _, user := range page {
+			if !seen[user.Id] {
+				seen[user.Id] = true
+				users = append(users, user)
+			}
+		}
+
+		if !sorted && limit > 0 && len(users) >= limit {
+			// Enough users found, stop paging
+			return 0, nil
+		}
+		return len(page), nil
+	})
+	return users, err
+}
+
+func dataSourceJumpCloudUsersRead(d *schema.ResourceData, m interface{}) error {
+	filter, err := buildUserSearchFilter(d.Get("filter").([]interface{}), d.Get("filter_match").(string))
+	if err != nil {
+		return err
+	}
+
+	body := jcapiv1.Search{}
+	if filter != nil {
+		body.Filter = &filter
+	}
+	if term := d.Get("search").(string); term != "" {
+		fields := []interface{}{"email", "username", "firstname", "lastname"}
+		if configured := d.Get("search_fields").([]interface{}); len(configured) > 0 {
+			fields = configured
+		}
+		var searchFilter interface{} = map[string]interface{}{
+			"searchTerm": term,
+			"fields":     fields,
+		}
+		body.SearchFilter = &searchFilter
+	}
+
+	sortBy := d.Get("sort").(string)
+	limit := d.Get("limit").(int)
+
+	users, err := searchUsers(searchSystemusers(m.(*jcapiv2.Configuration)), body, limit, sortBy != "")
+	if err != nil {
+		return err
+	}
+	if sortBy != "" {
+		sortUsers(users, sortBy)
+	}
+	if limit > 0 && len(users) > limit {
+		users = users[:limit]
+	}
+
+	result := make([]interface{}, 0, len(users))
+	for _, user := range users {
+		result = append(result, map[string]interface{}{
+			"id":                  user.Id,
+			"email":               user.Email,
+			"username":            user.Username,
+			"firstname":           user.Firstname,
+			"lastname":            user.Lastname,
+			"display_name":        user.Displayname,
+			"department":          user.Department,
+			"employee_identifier": user.EmployeeIdentifier,
+			"activated":           user.Activated,
+			"suspended":           user.Suspended,
+			"externally_managed":  user.ExternallyManaged,
+		})
+	}
+	if err := d.Set("users", result); err != nil {
+		return err
+	}
+
+	// The ID identifies the query, not its results
+	query, _ := json.Marshal([]interface{}{body, sortBy, limit})
+	d.SetId(fmt.Sprintf("%x", sha256.Sum256(query)))
+
+	return nil
+}
@@ -0,0 +1,140 @@
+package jumpcloud
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	jcapiv1 "github.com/TheJumpCloud/jcapi-go/v1"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestBuildUserSearchFilter(t *testing.T) {
+	filter, err := buildUserSearchFilter([]interface{}{
+		map[string]interface{}{"field": "department", "operator": "eq", "value": "Sales", "values": []interface{}{}},
+		map[string]interface{}{"field": "suspended", "operator": "eq", "value": "true", "values": []interface{}{}},
+		map[string]interface{}{"field": "email", "operator": "regex", "value": "@acme\\.org$", "values": []interface{}{}},
+		map[string]interface{}{"field": "employeeType", "operator": "in", "value": "", "values": []interface{}{"a", "b"}},
+		map[string]interface{}{"field": "jobTitle", "operator": "exists", "value": "", "values": []interface{}{}},
+	}, "and")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]interface{}{
+		"and": []interface{}{
+			map[string]interface{}{"department": "Sales"},
+			map[string]interface{}{"suspended": true},
+			map[string]interface{}{"email": map[string]interface{}{"$regex": "@acme\\.org$"}},
+			map[string]interface{}{"employeeType": map[string]interface{}{"$in": []interface{}{"a", "b"}}},
+			map[string]interface{}{"jobTitle": map[string]interface{}{"$exists": true}},
+		},
+	}
+	if !reflect.DeepEqual(filter, expected) {
+		t.Errorf("Expected %v, got %v", expected, filter)
+	}
+
+	if filter, _ := buildUserSearchFilter(nil, "or"); filter != nil {
+		t.Errorf("Expected no filter, got %v", filter)
+	}
+
+	_, err = buildUserSearchFilter([]interface{}{
+		map[string]interface{}{"field": "employeeType", "operator": "in", "value": "", "values": []interface{}{}},
+	}, "or")
+	if err == nil {
+		t.Error("Expected an error for the in operator without values")
+	}
+}
+
+func TestSortUsers(t *testing.T) {
+	users := []jcapiv1.Systemuserreturn{{Username: "b"}, {Username: "c"}, {Username: "a"}}
+
+	sortUsers(users, "username")
+	if users[0].Username != "a" || users[2].Username != "c" {
+		t.Errorf("Expected ascending order, got %v", users)
+	}
+
+	sortUsers(users, "-username")
+	if users[0].Username != "c" || users[2].Username != "a" {
+		t.Errorf("Expected descending order, got %v", users)
+	}
+}
+
+func TestDataSourceUsersBasic(t *testing.T) {
+	rName := acctest.RandStringFromCharSet(10, acctest.CharSetAlpha)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testDataSourceUsersConfigBasic(rName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.jumpcloud_users.test_users", "users.#", "2"),
+					resource.TestCheckResourceAttr("data.jumpcloud_users.test_users", "users.0.username", rName+"_a"),
+					resource.TestCheckResourceAttr("data.jumpcloud_users.test_users", "users.1.username", rName+"_b"),
+				),
+			},
+		},
+	})
+}
+
+func testDataSourceUsersConfigBasic(name string) string {
+	return fmt.Sprintf(`
+		resource "jumpcloud_user" "test_user_b" {
+			username = "%[1]s_b"
+			email    = "%[1]s_b@testorg.com"
+		}
+
+		resource "jumpcloud_user" "test_user_a" {
+			username = "%[1]s_a"
+			email    = "%[1]s_a@testorg.com"
+		}
+
+		data "jumpcloud_users" "test_users" {
+			filter {
+				field    = "username"
+				operator = "regex"
+				value    = "^%[1]s_"
+			}
+			sort = "username"
+
+			depends_on = [jumpcloud_user.test_user_a, jumpcloud_user.test_user_b]
+		}
+	`, name)
+}
+
+func TestSearchUsers(t *testing.T) {
+	// A user created while paging shifts the last user of the first page
+	// onto the second one
+	var all []jcapiv1.Systemuserreturn
+	for i := 0; i < 150; i++ {
+		all = append(all, jcapiv1.Systemuserreturn{Id: fmt.Sprintf("id%03d", i)})
+	}
+	calls := 0
+	search := func(body jcapiv1.Search, optionals map[string]interface{}) ([]jcapiv1.Systemuserreturn, error) {
+		calls++
+		if optionals["skip"].(int32) == 0 {
+			return all[:100], nil
+		}
+		return all[99:], nil
+	}
+
+	users, err := searchUsers(search, jcapiv1.Search{}, 0, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(users) != 150 || calls != 2 {
+		t.Errorf("Expected 150 distinct users from 2 pages, got %d from %d", len(users), calls)
+	}
+
+	calls = 0
+	if users, _ := searchUsers(search, jcapiv1.Search{}, 10, false); len(users) != 100 || calls != 1 {
+		t.Errorf("Expected paging to stop once the limit is reached, got %d users from %d pages", len(users), calls)
+	}
+	calls = 0
+	if users, _ := searchUsers(search, jcapiv1.Search{}, 10, true); len(users) != 150 || calls != 2 {
+		t.Errorf("Expected all pages to be read for sorting, got %d users from %d pages", len(users), calls)
+	}
+}
@@ -0,0 +1,292 @@
+package jumpcloud
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	jcapiv2 "github.com/TheJumpCloud/jcapi-go/v2"
+)
+
+const (
+	// maxConcurrentGroupOps is the maximum number of concurrent group membership operations
+	maxConcurrentGroupOps = 5
+	// groupOpRateLimitMs is the minimum time between operations per worker (rate limiting)
+	groupOpRateLimitMs = 20
+	// maxRetries is the maximum number of retries for API calls
+	maxRetries = 3
+	// baseBackoffMs is the base backoff time in milliseconds for exponential backoff
+	baseBackoffMs = 100
+)
+
+// groupOperation represents a single group membership operation
+type groupOperation struct {
+	groupID   string
+	groupName string
+	userID    string
+	op        string // "add" or "remove"
+}
+
+// membershipPoster applies a single group membership operation against the API
+type membershipPoster func(op groupOperation) (*http.Response, error)
+
+// postGroupMembership returns the membershipPoster backed by the v2 graph API
+func postGroupMembership(client *jcapiv2.APIClient) membershipPoster {
+	return func(op groupOperation) (*http.Response, error) {
+		payload := jcapiv2.UserGroupMembersReq{
+			Op:    op.op,
+			Type_: "user",
+			Id:    op.userID,
+		}
+		req := map[string]interface{}{
+			"body": payload,
+		}
+		return client.UserGroupMembersMembershipApi.GraphUserGroupMembersPost(
+			context.TODO(), op.groupID, "", "", req)
+	}
+}
+
+// syncUserGroups synchronizes a user's group memberships
+// It adds the user to new groups and removes from old groups
+func syncUserGroups(client *jcapiv2.APIClient, userID string, oldGroupIDs, newGroupIDs []string, groupNameToID map[string]string) error {
+	// Build reverse lookup for error messages
+	groupIDToName := make(map[string]string)
+	for name, id := range groupNameToID {
+		groupIDToName[id] = name
+	}
+
+	var operations []groupOperation
+	added, removed := diffIDs(oldGroupIDs, newGroupIDs)
+	for _, groupID := range added {
+		operations = append(operations, groupOperation{groupID: groupID, groupName: groupIDToName[groupID], userID: userID, op: "add"})
+	}
+	for _, groupID := range removed {
+		operations = append(operations, groupOperation{groupID: groupID, groupName: groupIDToName[groupID], userID: userID, op: "remove"})
+	}
+
+	return applyGroupOperations(postGroupMembership(client), operations)
+}
+
+// syncGroupMembers synchronizes the members of a single group
+// It adds new members and removes old ones
+func syncGroupMembers(client *jcapiv2.APIClient, groupID, groupName string, oldMemberIDs, newMemberIDs []string) error {
+	var operations []groupOperation
+	added, removed := diffIDs(oldMemberIDs, newMemberIDs)
+	for _, userID := range added {
+		operations = append(operations, groupOperation{groupID: groupID, groupName: groupName, userID: userID, op: "add"})
+	}
+	for _, userID := range removed {
+		operations = append(operations, groupOperation{groupID: groupID, groupName: groupName, userID: userID, op: "remove"})
+	}
+
+	return applyGroupOperations(postGroupMembership(client), operations)
+}
+
+// postUserSystemGroupAssociation returns the membershipPoster associating
+// users with system groups, for the users to administer them
+func postUserSystemGroupAssociation(client *jcapiv2.APIClient) membershipPoster {
+	return func(op groupOperation) (*http.Response, error) {
+		req := map[string]interface{}{
+			"body": jcapiv2.UserGraphManagementReq{
+				Op:    op.op,
+				Type_: "system_group",
+				Id:    op.groupID,
+			},
+		}
+		return client.UsersApi.GraphUserAssociationsPost(context.TODO(), op.userID, "", "", req)
+	}
+}
+
+// syncUserSystemGroups synchronizes the system groups a user is associated with
+func syncUserSystemGroups(client *jcapiv2.APIClient, userID string, oldGroupIDs, newGroupIDs []string) error {
+	var operations []groupOperation
+	added, removed := diffIDs(oldGroupIDs, newGroupIDs)
+	for _, groupID := range added {
+		operations = append(operations, groupOperation{groupID: groupID, userID: userID, op: "add"})
+	}
+	for _, groupID := range removed {
+		operations = append(operations, groupOperation{groupID: groupID, userID: userID, op: "remove"})
+	}
+
+	return applyGroupOperations(postUserSystemGroupAssociation(client), operations)
+}
+
+// diffIDs returns the IDs only in newIDs and the IDs only in oldIDs, ignoring
+// empty IDs and duplicates
+func diffIDs(oldIDs, newIDs []string) (added, removed []string) {
+	oldSet := make(map[string]bool)
+	for _, id := range oldIDs {
+		if id != "" {
+			oldSet[id] = true
+		}
+	}
+	newSet := make(map[string]bool)
+	for _, id := range newIDs {
+		if id != "" {
+			newSet[id] = true
+		}
+	}
+
+	for id := range newSet {
+		if !oldSet[id] {
+			added = append(added, id)
+		}
+	}
+	for id := range oldSet {
+		if !newSet[id] {
+			removed = append(removed, id)
+		}
+	}
+	sort.Strings(added)
+	sort.Strings(removed)
+	return added, removed
+}
+
+// applyGroupOperations processes group membership operations using a bounded
+// worker pool. Every failed operation is reported with the group it targeted.
+func applyGroupOperations(post membershipPoster, operations []groupOperation) error {
+	if len(operations) == 0 {
+		log.Println("[DEBUG] applyGroupOperations: No changes needed")
+		return nil
+	}
+
+	numWorkers := maxConcurrentGroupOps
+	if len(operations) < numWorkers {
+		numWorkers = len(operations)
+	}
+
+	log.Printf("[DEBUG] applyGroupOperations: Processing %d group operations concurrently (max %d workers)", len(operations), numWorkers)
+
+	// Channels for work distribution and results
+	opsChan := make(chan groupOperation, len(operations))
+	errChan := make(chan string, len(operations))
+
+	// Start workers
+	var wg sync.WaitGroup
+	for i := 0; i < numWorkers; i++ {
+		wg.Add(1)
+		go groupOperationWorker(post, opsChan, errChan, &wg)
+	}
+
+	// Send operations to workers
+	for _, op := range operations {
+		opsChan <- op
+	}
+	close(opsChan)
+
+	// Wait for all workers to complete
+	wg.Wait()
+	close(errChan)
+
+	// Collect errors
+	var errors []string
+	for errMsg := range errChan {
+		errors = append(errors, errMsg)
+	}
+	sort.Strings(errors)
+
+	log.Printf("[DEBUG] applyGroupOperations: Processed %d operations, %d errors", len(operations), len(errors))
+
+	if len(errors) > 0 {
+		return fmt.Errorf("group synchronization partially failed:\n%s", strings.Join(errors, "\n"))
+	}
+	return nil
+}
+
+// groupOperationWorker processes group operations from the channel with exponential backoff retry
+func groupOperationWorker(post membershipPoster, ops <-chan groupOperation, errors chan<- string, wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	for op := range ops {
+		if err := applyGroupOperation(post, op); err != nil {
+			log.Printf("[ERROR] %s", err.Error())
+			errors <- err.Error()
+		}
+
+		// Rate limiting between operations
+		time.Sleep(groupOpRateLimitMs * time.Millisecond)
+	}
+}
+
+// applyGroupOperation applies a single operation, retrying transient failures
+func applyGroupOperation(post membershipPoster, op groupOperation) error {
+	log.Printf("[DEBUG] applyGroupOperation: %s user %s, group %s", op.op, op.userID, op.describeGroup())
+
+	var lastErr error
+	for attempt := 0; attempt < maxRetries; attempt++ {
+		if attempt > 0 {
+			backoff := time.Duration(baseBackoffMs*(1<<attempt)) * time.Millisecond
+			log.Printf("[DEBUG] applyGroupOperation: Retry %d for %s user %s, group %s after %v",
+				attempt, op.op, op.userID, op.describeGroup(), backoff)
+			time.Sleep(backoff)
+		}
+
+		res, err := post(op)
+		if err == nil {
+			return nil
+		}
+		if isNoopMembershipResponse(op.op, res) {
+			log.Printf("[DEBUG] applyGroupOperation: Membership of user %s in group %s already in the requested state",
+				op.userID, op.describeGroup())
+			return nil
+		}
+
+		lastErr = fmt.Errorf("error %s user %s %s group %s: %s",
+			op.verb(), op.userID, op.preposition(), op.describeGroup(), err)
+		if !isRetryableResponse(res) {
+			break
+		}
+	}
+	return lastErr
+}
+
+// isNoopMembershipResponse reports whether a failed request left the
+// membership in the requested state anyway, i.e. the user already was a
+// member or wasn't a member in the first place
+func isNoopMembershipResponse(op string, res *http.Response) bool {
+	if res == nil {
+		return false
+	}
+	switch op {
+	case "add":
+		return res.StatusCode == http.StatusConflict
+	case "remove":
+		return res.StatusCode == http.StatusNotFound
+	}
+	return false
+}
+
+// isRetryableResponse reports whether a failed request may succeed when retried
+func isRetryableResponse(res *http.Response) bool {
+	if res == nil {
+		// Network errors
+		return true
+	}
+	return res.StatusCode == http.StatusTooManyRequests || res.StatusCode >= http.StatusInternalServerError
+}
+
+func (op groupOperation) describeGroup() string {
+	if op.groupName == "" {
+		return op.groupID
+	}
+	return fmt.Sprintf("%s (%s)", op.groupName, op.groupID)
+}
+
+func (op groupOperation) verb() string {
+	if op.op == "remove" {
+		return "removing"
+	}
+	return "adding"
+}
+
+func (op groupOperation) preposition() string {
+	if op.op == "remove" {
+		return "from"
+	}
+	return "to"
+}
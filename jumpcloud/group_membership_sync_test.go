@@ -0,0 +1,112 @@
+package jumpcloud
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// stubPoster records operations and answers them with the configured status codes
+type stubPoster struct {
+	mu        sync.Mutex
+	calls     map[string]int
+	responses map[string][]int // key: groupID/op, consumed in order
+}
+
+func newStubPoster(responses map[string][]int) *stubPoster {
+	return &stubPoster{calls: make(map[string]int), responses: responses}
+}
+
+func (s *stubPoster) post(op groupOperation) (*http.Response, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := op.groupID + "/" + op.op
+	s.calls[key]++
+
+	codes := s.responses[key]
+	if len(codes) == 0 {
+		return &http.Response{StatusCode: http.StatusNoContent}, nil
+	}
+	code := codes[0]
+	s.responses[key] = codes[1:]
+	if code < 300 {
+		return &http.Response{StatusCode: code}, nil
+	}
+	return &http.Response{StatusCode: code}, errors.New(http.StatusText(code))
+}
+
+func TestApplyGroupOperationsIdempotent(t *testing.T) {
+	stub := newStubPoster(map[string][]int{
+		"a/add":    {http.StatusConflict},
+		"b/remove": {http.StatusNotFound},
+	})
+
+	err := applyGroupOperations(stub.post, []groupOperation{
+		{groupID: "a", userID: "u", op: "add"},
+		{groupID: "b", userID: "u", op: "remove"},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	if stub.calls["a/add"] != 1 || stub.calls["b/remove"] != 1 {
+		t.Errorf("Expected no retries for no-op responses, got %v", stub.calls)
+	}
+}
+
+func TestApplyGroupOperationsRetries(t *testing.T) {
+	stub := newStubPoster(map[string][]int{
+		"a/add": {http.StatusInternalServerError, http.StatusTooManyRequests},
+	})
+
+	err := applyGroupOperations(stub.post, []groupOperation{
+		{groupID: "a", userID: "u", op: "add"},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	if stub.calls["a/add"] != 3 {
+		t.Errorf("Expected 3 attempts, got %d", stub.calls["a/add"])
+	}
+}
+
+func TestApplyGroupOperationsReportsEachGroup(t *testing.T) {
+	stub := newStubPoster(map[string][]int{
+		"a/add":    {http.StatusBadRequest},
+		"b/remove": {http.StatusForbidden},
+	})
+
+	err := applyGroupOperations(stub.post, []groupOperation{
+		{groupID: "a", groupName: "Developers", userID: "u", op: "add"},
+		{groupID: "b", userID: "u", op: "remove"},
+		{groupID: "c", userID: "u", op: "add"},
+	})
+	if err == nil {
+		t.Fatal("Expected an error")
+	}
+	if !strings.Contains(err.Error(), "adding user u to group Developers (a)") {
+		t.Errorf("Expected the failed add to name the group, got %s", err)
+	}
+	if !strings.Contains(err.Error(), "removing user u from group b") {
+		t.Errorf("Expected the failed remove to name the group, got %s", err)
+	}
+	if strings.Contains(err.Error(), "group c") {
+		t.Errorf("Expected successful operations not to be reported, got %s", err)
+	}
+	if stub.calls["a/add"] != 1 {
+		t.Errorf("Expected client errors not to be retried, got %d attempts", stub.calls["a/add"])
+	}
+}
+
+func TestDiffIDs(t *testing.T) {
+	added, removed := diffIDs([]string{"a", "b", "", "b"}, []string{"b", "c", "d"})
+
+	if strings.Join(added, ",") != "c,d" {
+		t.Errorf("Expected added [c d], got %v", added)
+	}
+	if strings.Join(removed, ",") != "a" {
+		t.Errorf("Expected removed [a], got %v", removed)
+	}
+}
@@ -25,18 +25,27 @@ func Provider() *schema.Provider {
 			},
 		},
 		ResourcesMap: map[string]*schema.Resource{
-			"jumpcloud_application":             resourceApplication(),
-			"jumpcloud_user":                    resourceUser(),
-			"jumpcloud_user_group":              resourceUserGroup(),
-			"jumpcloud_user_group_membership":   resourceUserGroupMembership(),
-			"jumpcloud_user_group_memberships":  resourceUserGroupMemberships(),
-			"jumpcloud_system_group":            resourceGroupsSystem(),
-			"jumpcloud_user_group_association":  resourceUserGroupAssociation(),
+			"jumpcloud_application":                    resourceApplication(),
+			"jumpcloud_user":                           resourceUser(),
+			"jumpcloud_user_group":                     resourceUserGroup(),
+			"jumpcloud_user_group_membership":          resourceUserGroupMembership(),
+			"jumpcloud_user_group_memberships":         resourceUserGroupMemberships(),
+			"jumpcloud_system_group":                   resourceGroupsSystem(),
+			"jumpcloud_user_group_association":         resourceUserGroupAssociation(),
+			"jumpcloud_user_association":               resourceUserAssociation(),
+			"jumpcloud_user_system_binding":            resourceUserSystemBinding(),
+			"jumpcloud_user_group_suggestion_approval": resourceUserGroupSuggestionApproval(),
+			"jumpcloud_user_group_members":             resourceUserGroupMembers(),
 		},
 		DataSourcesMap: map[string]*schema.Resource{
-			"jumpcloud_user":        dataSourceJumpCloudUser(),
-			"jumpcloud_user_group":  dataSourceJumpCloudUserGroup(),
-			"jumpcloud_application": dataSourceJumpCloudApplication(),
+			"jumpcloud_user":                   dataSourceJumpCloudUser(),
+			"jumpcloud_users":                  dataSourceJumpCloudUsers(),
+			"jumpcloud_user_effective_access":  dataSourceJumpCloudUserEffectiveAccess(),
+			"jumpcloud_user_group":             dataSourceJumpCloudUserGroup(),
+			"jumpcloud_application":            dataSourceJumpCloudApplication(),
+			"jumpcloud_application_users":      dataSourceJumpCloudApplicationUsers(),
+			"jumpcloud_system_users":           dataSourceJumpCloudSystemUsers(),
+			"jumpcloud_user_group_suggestions": dataSourceJumpCloudUserGroupSuggestions(),
 		},
 		ConfigureFunc: providerConfigure,
 	}
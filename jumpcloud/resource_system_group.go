@@ -3,6 +3,7 @@ package jumpcloud
 import (
 	"context"
 	"fmt"
+	"net/http"
 
 	jcapiv2 "github.com/TheJumpCloud/jcapi-go/v2"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -19,6 +20,16 @@ func resourceGroupsSystem() *schema.Resource {
 				Type:     schema.TypeString,
 				Optional: true,
 			},
+			"description": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Description of the group, e.g. its owner",
+			},
+			"email": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "E-mail address of the group, e.g. of its owner",
+			},
 			"jc_id": {
 				Type:     schema.TypeString,
 				Computed: true,
@@ -32,27 +43,29 @@ func resourceGroupsSystem() *schema.Resource {
 
 func resourceGroupsSystemCreate(d *schema.ResourceData, m interface{}) error {
 	config := m.(*jcapiv2.Configuration)
-	client := jcapiv2.NewAPIClient(config)
 
-	body := jcapiv2.SystemGroupData{Name: d.Get("name").(string)}
+	body := expandSystemGroupData(d)
 
-	req := map[string]interface{}{
-		"body": body,
-	}
-	group, res, err := client.SystemGroupsApi.GroupsSystemPost(context.TODO(),
-		"", headerAccept, req)
-	if err != nil {
-		// TODO: sort out error essentials
-		return fmt.Errorf("error creating system group %s: %s - response = %+v",
-			(req["body"].(jcapiv2.SystemGroupData)).Name, err, res)
+	// The SDK's SystemGroupData lacks the description and email
+	var group SystemGroup
+	if err := doAPIRequest(config, http.MethodPost, config.BasePath+"/systemgroups", body, &group); err != nil {
+		return fmt.Errorf("error creating system group %s: %s", body.Name, err)
 	}
 
 	d.SetId(group.Name)
 	d.Set("name", group.Name)
-	d.Set("jc_id", group.Id)
+	d.Set("jc_id", group.ID)
 	return resourceGroupsSystemRead(d, m)
 }
 
+func expandSystemGroupData(d *schema.ResourceData) SystemGroupData {
+	return SystemGroupData{
+		Name:        d.Get("name").(string),
+		Description: d.Get("description").(string),
+		Email:       d.Get("email").(string),
+	}
+}
+
 // Helper to look up a system group by name
 func resourceGroupsSystemList_match(d *schema.ResourceData, m interface{}) (jcapiv2.SystemGroup, error) {
 	config := m.(*jcapiv2.Configuration)
@@ -81,7 +94,6 @@ func resourceGroupsSystemList_match(d *schema.ResourceData, m interface{}) (jcap
 
 func resourceGroupsSystemRead(d *schema.ResourceData, m interface{}) error {
 	config := m.(*jcapiv2.Configuration)
-	client := jcapiv2.NewAPIClient(config)
 
 	var id string
 
@@ -99,44 +111,36 @@ func resourceGroupsSystemRead(d *schema.ResourceData, m interface{}) error {
 		d.Set("jc_id", id_lookup.Id)
 	}
 
-	group, res, err := client.SystemGroupsApi.GroupsSystemGet(context.TODO(),
-		id, "", headerAccept, nil)
-	if err != nil {
-		// TODO: sort out error essentials
-		return fmt.Errorf("error reading system group ID %s: %s - response = %+v",
-			d.Id(), err, res)
+	// The SDK's SystemGroup lacks the description and email
+	var group SystemGroup
+	if err := doAPIRequest(config, http.MethodGet, config.BasePath+"/systemgroups/"+id, nil, &group); err != nil {
+		return fmt.Errorf("error reading system group ID %s: %s", d.Id(), err)
 	}
 
 	d.SetId(group.Name)
 	d.Set("name", group.Name)
-	d.Set("jc_id", group.Id)
+	d.Set("description", group.Description)
+	d.Set("email", group.Email)
+	d.Set("jc_id", group.ID)
 	return nil
 }
 
 func resourceGroupsSystemUpdate(d *schema.ResourceData, m interface{}) error {
 	config := m.(*jcapiv2.Configuration)
-	client := jcapiv2.NewAPIClient(config)
 
 	var id string
 	id = d.Get("jc_id").(string)
 
-	body := jcapiv2.SystemGroupData{Name: d.Get("name").(string)}
+	body := expandSystemGroupData(d)
 
-	req := map[string]interface{}{
-		"body": body,
-	}
-
-	group, res, err := client.SystemGroupsApi.GroupsSystemPut(context.TODO(),
-		id, "", headerAccept, req)
-	if err != nil {
-		// TODO: sort out error essentials
-		return fmt.Errorf("error updating system group %s: %s - response = %+v",
-			d.Get("name"), err, res)
+	var group SystemGroup
+	if err := doAPIRequest(config, http.MethodPut, config.BasePath+"/systemgroups/"+id, body, &group); err != nil {
+		return fmt.Errorf("error updating system group %s: %s", d.Get("name"), err)
 	}
 
 	d.SetId(group.Name)
 	d.Set("name", group.Name)
-	d.Set("jc_id", group.Id)
+	d.Set("jc_id", group.ID)
 	return resourceGroupsSystemRead(d, m)
 }
 
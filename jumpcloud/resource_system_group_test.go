@@ -33,3 +33,38 @@ func testSystemGroupResourceConfigBasic(name string) string {
 		}`, name,
 	)
 }
+
+func TestSystemGroupResourceDescription(t *testing.T) {
+	rName := acctest.RandStringFromCharSet(10, acctest.CharSetAlpha)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: nil,
+		Steps: []resource.TestStep{
+			{
+				Config: testSystemGroupResourceConfigDescription(rName, "first"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("jumpcloud_system_group.test_group", "description", "first"),
+					resource.TestCheckResourceAttr("jumpcloud_system_group.test_group", "email", rName+"@testorg.com"),
+				),
+			},
+			{
+				Config: testSystemGroupResourceConfigDescription(rName, "second"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("jumpcloud_system_group.test_group", "description", "second"),
+				),
+			},
+		},
+	})
+}
+
+func testSystemGroupResourceConfigDescription(name, description string) string {
+	return fmt.Sprintf(`
+		resource "jumpcloud_system_group" "test_group" {
+			name        = "%[1]s"
+			description = "%[2]s"
+			email       = "%[1]s@testorg.com"
+		}`, name, description,
+	)
+}
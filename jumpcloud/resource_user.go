@@ -3,22 +3,53 @@ package jumpcloud
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"log"
+	"net/http"
+	"sort"
 	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 
 	jcapiv1 "github.com/TheJumpCloud/jcapi-go/v1"
 	jcapiv2 "github.com/TheJumpCloud/jcapi-go/v2"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
+const (
+	// groupsModeAuthoritative makes groups reflect every group the user belongs to
+	groupsModeAuthoritative = "authoritative"
+	// groupsModeAdditive limits groups to the groups managed by the resource
+	groupsModeAdditive = "additive"
+
+	// activationEmailSend sends the activation email when the user is created
+	activationEmailSend = "send"
+	// activationEmailSkip never sends the activation email
+	activationEmailSkip = "skip"
+	// activationEmailOnStateChange also resends the activation email to users
+	// who haven't activated their account yet when they are unsuspended or
+	// their e-mail address changes
+	activationEmailOnStateChange = "on_state_change"
+
+	activationEmailType    = "activation"
+	passwordResetEmailType = "password_reset"
+)
+
 func resourceUser() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceUserCreate,
-		Read:   resourceUserRead,
-		Update: resourceUserUpdate,
-		Delete: resourceUserDelete,
+		CreateContext: resourceUserCreate,
+		ReadContext:   resourceUserRead,
+		UpdateContext: resourceUserUpdate,
+		DeleteContext: resourceUserDelete,
+		CustomizeDiff: customdiff.All(
+			validateRestrictedUserFields,
+			warnRestrictedUserFields,
+			warnUnboundLdapBindingUser,
+			validateUniqueUserIdentity,
+		),
 		Schema: map[string]*schema.Schema{
 			"username": {
 				Type:     schema.TypeString,
@@ -29,48 +60,68 @@ func resourceUser() *schema.Resource {
 				Required: true,
 			},
 			"firstname": {
-				Type:     schema.TypeString,
-				Optional: true,
+				Type:             schema.TypeString,
+				Optional:         true,
+				DiffSuppressFunc: suppressRestrictedUserField,
 			},
 			"lastname": {
-				Type:     schema.TypeString,
-				Optional: true,
+				Type:             schema.TypeString,
+				Optional:         true,
+				DiffSuppressFunc: suppressRestrictedUserField,
 			},
 			"display_name": {
-				Type:     schema.TypeString,
-				Optional: true,
+				Type:             schema.TypeString,
+				Optional:         true,
+				DiffSuppressFunc: suppressRestrictedUserField,
 			},
 			"password": {
-				Type:     schema.TypeString,
-				Optional: true,
+				Type:             schema.TypeString,
+				Optional:         true,
+				DiffSuppressFunc: suppressRestrictedUserField,
 			},
 			"enable_mfa": {
-				Type:     schema.TypeBool,
-				Optional: true,
+				Type:             schema.TypeBool,
+				Optional:         true,
+				DiffSuppressFunc: suppressRestrictedUserField,
 			},
 			"ldap_binding_user": {
-				Type:     schema.TypeBool,
+				Type:             schema.TypeBool,
+				Optional:         true,
+				DiffSuppressFunc: suppressLdapBindingUser,
+			},
+			"ldap_servers": {
+				Type:     schema.TypeSet,
 				Optional: true,
+				Description: "Set of LDAP server IDs the user is directly bound to. Bindings to other servers are left alone. " +
+					"Unless ldap_binding_user is configured, binding the user to an LDAP server makes it an LDAP binding user",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
 			},
 			"passwordless_sudo": {
-				Type:     schema.TypeBool,
-				Optional: true,
+				Type:             schema.TypeBool,
+				Optional:         true,
+				DiffSuppressFunc: suppressRestrictedUserField,
 			},
 			"password_never_expires": {
-				Type:     schema.TypeBool,
-				Optional: true,
+				Type:             schema.TypeBool,
+				Optional:         true,
+				DiffSuppressFunc: suppressRestrictedUserField,
 			},
 			"sudo": {
-				Type:     schema.TypeBool,
-				Optional: true,
+				Type:             schema.TypeBool,
+				Optional:         true,
+				DiffSuppressFunc: suppressRestrictedUserField,
 			},
 			"suspended": {
-				Type:     schema.TypeBool,
-				Optional: true,
+				Type:             schema.TypeBool,
+				Optional:         true,
+				DiffSuppressFunc: suppressRestrictedUserField,
 			},
 			"phone_number": {
-				Type:     schema.TypeList,
-				Optional: true,
+				Type:             schema.TypeList,
+				Optional:         true,
+				DiffSuppressFunc: suppressRestrictedUserField,
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
 						"number": {
@@ -91,9 +142,89 @@ func resourceUser() *schema.Resource {
 				},
 			},
 			"groups": {
+				Type:             schema.TypeSet,
+				Optional:         true,
+				Description:      "Set of group IDs this user belongs to",
+				ConflictsWith:    []string{"group_names"},
+				DiffSuppressFunc: suppressIfConfigured("group_names"),
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"group_names": {
+				Type:             schema.TypeSet,
+				Optional:         true,
+				Description:      "Set of group names this user belongs to, as an alternative to groups",
+				ConflictsWith:    []string{"groups"},
+				DiffSuppressFunc: suppressIfConfigured("groups"),
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"groups_mode": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  groupsModeAuthoritative,
+				Description: "Either authoritative, to remove the user from groups that are not configured, " +
+					"or additive, to only manage the configured groups and ignore all others",
+				ValidateFunc: validation.StringInSlice([]string{
+					groupsModeAuthoritative,
+					groupsModeAdditive,
+				}, false),
+			},
+			"managed_groups": {
 				Type:        schema.TypeSet,
+				Computed:    true,
+				Description: "Set of group IDs the resource added the user to",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"group_name_ids": {
+				Type:        schema.TypeMap,
+				Computed:    true,
+				Description: "Map of the names in group_names to the group IDs they were resolved to",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"activation_email": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  activationEmailSkip,
+				Description: "Either send, to send the activation email when the user is created, skip, " +
+					"or on_state_change, to also resend it to users who haven't activated their account " +
+					"when they are unsuspended or their e-mail address changes",
+				ValidateFunc: validation.StringInSlice([]string{
+					activationEmailSend,
+					activationEmailSkip,
+					activationEmailOnStateChange,
+				}, false),
+			},
+			"reset_password_trigger": {
+				Type:        schema.TypeString,
 				Optional:    true,
-				Description: "Set of group IDs this user belongs to",
+				Description: "Arbitrary value; changing it sends a password reset email to the user",
+			},
+			"activated": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether the user has activated its account",
+			},
+			"externally_managed": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether the user is provisioned by a directory integration such as an HRIS",
+			},
+			"external_source_type": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The type of the integration managing this user, if any",
+			},
+			"restricted_fields": {
+				Type:        schema.TypeSet,
+				Computed:    true,
+				Description: "JumpCloud fields owned by an external system that cannot be changed from Terraform",
 				Elem: &schema.Schema{
 					Type: schema.TypeString,
 				},
@@ -102,11 +233,29 @@ func resourceUser() *schema.Resource {
 			// JumpCloud offers a lot more
 		},
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			State: userImporter,
 		},
 	}
 }
 
+// userImporter treats all current groups of an imported user as managed by
+// the resource, which is what the default authoritative groups_mode implies.
+// Imported users never get an activation email.
+func userImporter(d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	client := jcapiv2.NewAPIClient(m.(*jcapiv2.Configuration))
+
+	groupIDs, err := getUserGroupIDs(client, d.Id())
+	if err != nil {
+		return nil, err
+	}
+
+	_ = d.Set("groups_mode", groupsModeAuthoritative)
+	_ = d.Set("activation_email", activationEmailSkip)
+	_ = d.Set("managed_groups", groupIDs)
+
+	return []*schema.ResourceData{d}, nil
+}
+
 // We receive a v2config from the TF base code but need a v1config to continue. So, we take the only
 // preloaded element (the x-api-key) and populate the v1config with it.
 func convertV2toV1Config(v2config *jcapiv2.Configuration) *jcapiv1.Configuration {
@@ -118,14 +267,447 @@ func convertV2toV1Config(v2config *jcapiv2.Configuration) *jcapiv1.Configuration
 	return configv1
 }
 
-func resourceUserCreate(d *schema.ResourceData, m interface{}) error {
+// userReadHelper consumes the JC's HTTP API directly, as the SDK's
+// Systemuserreturn lacks the restrictedFields set by directory integrations
+func userReadHelper(config *jcapiv2.Configuration, id string) (user *SystemUser,
+	ok bool, err error) {
+
+	configv1 := convertV2toV1Config(config)
+	req, err := newAPIRequest(config, http.MethodGet,
+		configv1.BasePath+"/systemusers/"+id, nil)
+	if err != nil {
+		return
+	}
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusNotFound {
+		return
+	}
+	if res.StatusCode >= 300 {
+		err = fmt.Errorf("error reading user %s: %s", id, res.Status)
+		return
+	}
+
+	ok = true
+	err = json.NewDecoder(res.Body).Decode(&user)
+	return
+}
+
+// suppressRestrictedUserField hides the diff of an attribute that is owned by
+// an external system and not set in the configuration, as Terraform would
+// otherwise try to clear a value it never managed
+func suppressRestrictedUserField(k, old, new string, d *schema.ResourceData) bool {
+	attr := strings.SplitN(k, ".", 2)[0]
+	restricted, ok := d.GetOk("restricted_fields")
+	if !ok || !restrictedUserAttributes(restricted.(*schema.Set).List())[attr] {
+		return false
+	}
+
+	config := d.GetRawConfig()
+	if config.IsNull() || !config.IsKnown() {
+		return false
+	}
+	return config.GetAttr(attr).IsNull()
+}
+
+// suppressLdapBindingUser ignores the binding-user flag implied by ldap_servers
+// when ldap_binding_user itself isn't configured
+func suppressLdapBindingUser(k, old, new string, d *schema.ResourceData) bool {
+	if suppressRestrictedUserField(k, old, new, d) {
+		return true
+	}
+	return !isConfigured(d, "ldap_binding_user") && d.Get("ldap_servers").(*schema.Set).Len() > 0
+}
+
+// ldapBindingUser returns the binding-user flag to send to JumpCloud
+func ldapBindingUser(d *schema.ResourceData) bool {
+	if isConfigured(d, "ldap_binding_user") {
+		return d.Get("ldap_binding_user").(bool)
+	}
+	return d.Get("ldap_binding_user").(bool) || d.Get("ldap_servers").(*schema.Set).Len() > 0
+}
+
+// syncUserLdapServers binds the user to the LDAP servers only in newServerIDs
+// and unbinds it from the ones only in oldServerIDs
+func syncUserLdapServers(client *jcapiv2.APIClient, userID string, oldServerIDs, newServerIDs []string) error {
+	added, removed := diffIDs(oldServerIDs, newServerIDs)
+	for _, op := range []struct {
+		action string
+		ids    []string
+	}{{"add", added}, {"remove", removed}} {
+		for _, serverID := range op.ids {
+			req := map[string]interface{}{
+				"body": jcapiv2.UserGraphManagementReq{
+					Op:    op.action,
+					Type_: "ldap_server",
+					Id:    serverID,
+				},
+			}
+			res, err := client.UsersApi.GraphUserAssociationsPost(context.TODO(), userID, "", "", req)
+			if err != nil {
+				return fmt.Errorf("error managing ldap server binding, action: %s, user id: %s, ldap server id: %s, error: %s; response = %+v",
+					op.action, userID, serverID, err, res)
+			}
+		}
+	}
+	return nil
+}
+
+// readUserLdapServers returns the IDs of the LDAP servers the user is directly bound to
+func readUserLdapServers(config *jcapiv2.Configuration, userID string) ([]string, error) {
+	associations, _, err := listUserAssociations(sdkUserAssociations(jcapiv2.NewAPIClient(config), userID, "ldap_server"))
+	if err != nil {
+		return nil, err
+	}
+	serverIDs := make([]string, 0, len(associations))
+	for _, v := range associations {
+		if v.To != nil {
+			serverIDs = append(serverIDs, v.To.Id)
+		}
+	}
+	return serverIDs, nil
+}
+
+// hasLdapServerAccess reports whether the user can be found in any LDAP
+// server, either through a direct binding or through one of its groups
+func hasLdapServerAccess(client *jcapiv2.APIClient, userID string) (bool, error) {
+	servers, _, err := client.UsersApi.GraphUserTraverseLdapServer(context.TODO(), userID, "", headerAccept,
+		map[string]interface{}{"limit": int32(1)})
+	if err != nil {
+		return false, err
+	}
+	return len(servers) > 0, nil
+}
+
+// sendUserEmail sends an email of the given type to the user. As the user
+// already exists at this point, failures are returned as warnings.
+func sendUserEmail(ctx context.Context, client *jcapiv2.APIClient, userID, emailType string) diag.Diagnostics {
+	req := map[string]interface{}{
+		"body": jcapiv2.Emailrequest{
+			EmailType: emailType,
+		},
+	}
+	res, err := client.UsersApi.UsersSendEmails(ctx, userID, "", "", req)
+	if err != nil {
+		return diag.Diagnostics{{
+			Severity: diag.Warning,
+			Summary:  fmt.Sprintf("Unable to send %s email to user %s", strings.ReplaceAll(emailType, "_", " "), userID),
+			Detail:   fmt.Sprintf("%s; response = %+v", err, res),
+		}}
+	}
+	return nil
+}
+
+// shouldResendActivationEmail reports whether an update of a user who hasn't
+// activated its account yet calls for another activation email
+func shouldResendActivationEmail(d *schema.ResourceData) bool {
+	if d.Get("activation_email").(string) != activationEmailOnStateChange || d.Get("activated").(bool) {
+		return false
+	}
+	oldSuspended, newSuspended := d.GetChange("suspended")
+	unsuspended := oldSuspended.(bool) && !newSuspended.(bool)
+	return unsuspended || d.HasChange("email")
+}
+
+// validateRestrictedUserFields fails the plan when the configuration tries
+// to change a field that is owned by an external system
+func validateRestrictedUserFields(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+	if d.Id() == "" {
+		return nil
+	}
+
+	restricted := restrictedUserAttributes(d.Get("restricted_fields").(*schema.Set).List())
+	var changed []string
+	for attr := range restricted {
+		if d.HasChange(attr) {
+			changed = append(changed, attr)
+		}
+	}
+	if len(changed) == 0 {
+		return nil
+	}
+
+	sort.Strings(changed)
+	return fmt.Errorf("user %s is managed by %s; the following attributes are restricted "+
+		"and cannot be changed from Terraform: %s", d.Get("username").(string),
+		externalSourceName(d.Get("external_source_type").(string)), strings.Join(changed, ", "))
+}
+
+// warnRestrictedUserFields names the attributes owned by an external system
+// when planning the user. CustomizeDiff can't return warnings, so the warning
+// goes to the Terraform log.
+func warnRestrictedUserFields(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+	restricted := restrictedUserAttributes(d.Get("restricted_fields").(*schema.Set).List())
+	if len(restricted) == 0 {
+		return nil
+	}
+
+	log.Printf("[WARN] User %s has attributes owned by %s, which will not be updated by Terraform: %s",
+		d.Get("username").(string), externalSourceName(d.Get("external_source_type").(string)),
+		strings.Join(sortedRestrictedAttributes(restricted), ", "))
+	return nil
+}
+
+// warnUnboundLdapBindingUser warns when the planned user is an LDAP binding
+// user that isn't bound to any LDAP server. The planned ldap_servers decide
+// it if set; otherwise existing users are looked up, as they may be bound
+// through a group or a jumpcloud_user_association resource.
+func warnUnboundLdapBindingUser(_ context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if d.Id() != "" && !d.HasChanges("ldap_binding_user", "ldap_servers") {
+		return nil
+	}
+	if !d.Get("ldap_binding_user").(bool) || !d.NewValueKnown("ldap_servers") ||
+		d.Get("ldap_servers").(*schema.Set).Len() > 0 {
+		return nil
+	}
+
+	if d.Id() != "" {
+		if !planChecksEnabled(m) {
+			log.Println("[DEBUG] warnUnboundLdapBindingUser: Plan-time checks disabled, skipping")
+			return nil
+		}
+		bound, err := hasLdapServerAccess(jcapiv2.NewAPIClient(m.(*jcapiv2.Configuration)), d.Id())
+		if err != nil {
+			log.Printf("[DEBUG] warnUnboundLdapBindingUser: Unable to look up the LDAP servers of user %s: %s", d.Id(), err)
+			return nil
+		}
+		if bound {
+			return nil
+		}
+	}
+
+	log.Printf("[WARN] User %s is an LDAP binding user but isn't bound to any LDAP server. Bind the user to an "+
+		"LDAP server with ldap_servers, jumpcloud_user_association or a user group associated with the server, "+
+		"otherwise it cannot bind to LDAP.", d.Get("username").(string))
+	return nil
+}
+
+// validateUniqueUserIdentity looks up the planned username and email, so
+// that a collision with an existing user fails the plan instead of the apply
+func validateUniqueUserIdentity(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if d.Id() != "" && !d.HasChange("username") && !d.HasChange("email") {
+		return nil
+	}
+	if !d.NewValueKnown("username") || !d.NewValueKnown("email") {
+		return nil
+	}
+	if !planChecksEnabled(m) {
+		log.Println("[DEBUG] validateUniqueUserIdentity: Plan-time checks disabled, skipping")
+		return nil
+	}
+
+	username := d.Get("username").(string)
+	email := d.Get("email").(string)
+
+	configv1 := convertV2toV1Config(m.(*jcapiv2.Configuration))
+	client := jcapiv1.NewAPIClient(configv1)
+
+	var filter interface{} = map[string]interface{}{
+		"or": []interface{}{
+			map[string]interface{}{"username": username},
+			map[string]interface{}{"email": email},
+		},
+	}
+	res, _, err := client.SearchApi.SearchSystemusersPost(ctx, "application/json", "application/json", map[string]interface{}{
+		"body": jcapiv1.Search{
+			Filter: &filter,
+			Fields: "_id username email",
+		},
+		"limit": int32(100),
+	})
+	if err != nil {
+		return fmt.Errorf("error checking uniqueness of user %s: %s", username, err)
+	}
+
+	var collisions []string
+	for _, user := range res.Results {
+		if user.Id == d.Id() {
+			continue
+		}
+		if strings.EqualFold(user.Username, username) {
+			collisions = append(collisions, fmt.Sprintf("username %q is already used by user %s", username, user.Id))
+		}
+		if strings.EqualFold(user.Email, email) {
+			collisions = append(collisions, fmt.Sprintf("email %q is already used by user %s", email, user.Id))
+		}
+	}
+	if len(collisions) > 0 {
+		return fmt.Errorf("user %s conflicts with existing users:\n%s", username, strings.Join(collisions, "\n"))
+	}
+	return nil
+}
+
+// desiredUserGroupIDs returns the group IDs the user should belong to, either
+// taken from groups or resolved from group_names. The second return value maps
+// the resolved names to their IDs.
+func desiredUserGroupIDs(d *schema.ResourceData, client *jcapiv2.APIClient) ([]string, map[string]string, error) {
+	if !isConfigured(d, "group_names") {
+		groupsSet := d.Get("groups").(*schema.Set)
+		groupIDs := make([]string, groupsSet.Len())
+		for i, groupID := range groupsSet.List() {
+			groupIDs[i] = groupID.(string)
+		}
+		return groupIDs, map[string]string{}, nil
+	}
+
+	namesSet := d.Get("group_names").(*schema.Set)
+	names := make([]string, namesSet.Len())
+	for i, name := range namesSet.List() {
+		names[i] = name.(string)
+	}
+
+	nameToID, err := resolveNewGroupNames(names, d.Get("group_name_ids").(map[string]interface{}),
+		func(newNames []string) (map[string]string, error) {
+			return resolveUserGroupNames(client, newNames)
+		})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	groupIDs := make([]string, 0, len(nameToID))
+	for _, id := range nameToID {
+		groupIDs = append(groupIDs, id)
+	}
+	return groupIDs, nameToID, nil
+}
+
+// resolveNewGroupNames maps the names to group IDs, taking the IDs of names
+// resolved before from known, so that groups renamed in the console keep
+// their membership. Only the other names are passed to resolve.
+func resolveNewGroupNames(names []string, known map[string]interface{},
+	resolve func(names []string) (map[string]string, error)) (map[string]string, error) {
+	nameToID := make(map[string]string, len(names))
+	var newNames []string
+	for _, name := range names {
+		if id, ok := known[name].(string); ok && id != "" {
+			nameToID[name] = id
+		} else {
+			newNames = append(newNames, name)
+		}
+	}
+	if len(newNames) == 0 {
+		return nameToID, nil
+	}
+
+	resolved, err := resolve(newNames)
+	if err != nil {
+		return nil, err
+	}
+	for name, id := range resolved {
+		nameToID[name] = id
+	}
+	return nameToID, nil
+}
+
+// resolveUserGroupNames looks up groups with the exact matching of
+// lookupGroupsByName. Unknown names are reported together with the existing
+// groups they most likely refer to.
+func resolveUserGroupNames(client *jcapiv2.APIClient, names []string) (map[string]string, error) {
+	nameToID, err := lookupGroupsByName(client, names)
+	var notFound *groupsNotFoundError
+	if !errors.As(err, &notFound) {
+		return nameToID, err
+	}
+
+	groups, listErr := listUserGroups(client)
+	if listErr != nil {
+		log.Printf("[WARN] resolveUserGroupNames: Unable to list groups for suggestions: %s", listErr)
+		return nil, err
+	}
+	existing := make([]string, len(groups))
+	for i, group := range groups {
+		existing[i] = group.Name
+	}
+
+	msgs := make([]string, len(notFound.names))
+	for i, name := range notFound.names {
+		msgs[i] = fmt.Sprintf("%q", name)
+		if matches := closeMatches(name, existing); len(matches) > 0 {
+			quoted := make([]string, len(matches))
+			for j, match := range matches {
+				quoted[j] = fmt.Sprintf("%q", match)
+			}
+			msgs[i] += fmt.Sprintf(" (did you mean %s?)", strings.Join(quoted, ", "))
+		}
+	}
+	return nil, fmt.Errorf("user groups not found: %s", strings.Join(msgs, ", "))
+}
+
+// filterManagedGroupIDs returns the group IDs that are in the managed set
+func filterManagedGroupIDs(groupIDs []string, managed *schema.Set) []string {
+	filtered := make([]string, 0, len(groupIDs))
+	for _, id := range groupIDs {
+		if managed.Contains(id) {
+			filtered = append(filtered, id)
+		}
+	}
+	return filtered
+}
+
+// readUserGroupNames sets group_names from the user's group IDs if the
+// resource uses names. Names from the configuration are kept for the IDs they
+// were resolved to, so that renaming a group in the console doesn't produce a
+// diff.
+func readUserGroupNames(d *schema.ResourceData, client *jcapiv2.APIClient, groupIDs []string) error {
+	groupNameIDs := d.Get("group_name_ids").(map[string]interface{})
+	if len(groupNameIDs) == 0 && d.Get("group_names").(*schema.Set).Len() == 0 {
+		return nil
+	}
+
+	idToName := make(map[string]string, len(groupNameIDs))
+	for name, id := range groupNameIDs {
+		idToName[id.(string)] = name
+	}
+
+	var unknownIDs []string
+	for _, id := range groupIDs {
+		if _, ok := idToName[id]; !ok {
+			unknownIDs = append(unknownIDs, id)
+		}
+	}
+	lookedUp, err := getGroupIDToNameMap(client, unknownIDs)
+	if err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(groupIDs))
+	for _, id := range groupIDs {
+		if name, ok := idToName[id]; ok {
+			names = append(names, name)
+		} else if name, ok := lookedUp[id]; ok {
+			names = append(names, name)
+		}
+	}
+	return d.Set("group_names", names)
+}
+
+func externalSourceName(sourceType string) string {
+	if sourceType == "" {
+		return "an external directory"
+	}
+	return sourceType
+}
+
+func resourceUserCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	configv1 := convertV2toV1Config(m.(*jcapiv2.Configuration))
 	client := jcapiv1.NewAPIClient(configv1)
+	clientv2 := jcapiv2.NewAPIClient(m.(*jcapiv2.Configuration))
+
+	// Resolve the groups first, so that unknown group names fail before the user exists
+	newGroupIDs, groupNameIDs, err := desiredUserGroupIDs(d, clientv2)
+	if err != nil {
+		return diag.FromErr(err)
+	}
 
 	var phoneNumbers []jcapiv1.SystemuserputpostPhoneNumbers
 	phoneNumbersRaw, _ := json.Marshal(expandPhoneNumbers(d.Get("phone_number").([]interface{})))
 	if err := json.Unmarshal(phoneNumbersRaw, &phoneNumbers); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	payload := jcapiv1.Systemuserputpost{
@@ -136,7 +718,7 @@ func resourceUserCreate(d *schema.ResourceData, m interface{}) error {
 		Displayname:                 d.Get("display_name").(string),
 		Password:                    d.Get("password").(string),
 		EnableUserPortalMultifactor: d.Get("enable_mfa").(bool),
-		LdapBindingUser:             d.Get("ldap_binding_user").(bool),
+		LdapBindingUser:             ldapBindingUser(d),
 		Sudo:                        d.Get("sudo").(bool),
 		Suspended:                   d.Get("suspended").(bool),
 		PasswordNeverExpires:        d.Get("password_never_expires").(bool),
@@ -145,84 +727,101 @@ func resourceUserCreate(d *schema.ResourceData, m interface{}) error {
 	req := map[string]interface{}{
 		"body": payload,
 	}
-	returnstruc, _, err := client.SystemusersApi.SystemusersPost(context.TODO(),
+	returnstruc, _, err := client.SystemusersApi.SystemusersPost(ctx,
 		"", "", req)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	d.SetId(returnstruc.Id)
 
 	// Sync group memberships if groups are specified
-	if v, ok := d.GetOk("groups"); ok {
-		groupsSet := v.(*schema.Set)
-		newGroupIDs := make([]string, groupsSet.Len())
-		for i, groupID := range groupsSet.List() {
-			newGroupIDs[i] = groupID.(string)
+	if len(newGroupIDs) > 0 {
+		// Sync from empty list to the desired groups
+		if err := syncUserGroups(clientv2, returnstruc.Id, []string{}, newGroupIDs, groupNameIDs); err != nil {
+			return diag.FromErr(err)
 		}
+	}
+	if err := d.Set("group_name_ids", groupNameIDs); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("managed_groups", newGroupIDs); err != nil {
+		return diag.FromErr(err)
+	}
 
-		configv2 := m.(*jcapiv2.Configuration)
-		clientv2 := jcapiv2.NewAPIClient(configv2)
-
-		// Sync from empty list to the desired groups
-		if err := syncUserGroups(clientv2, returnstruc.Id, []string{}, newGroupIDs); err != nil {
-			return err
+	if ldapServers := d.Get("ldap_servers").(*schema.Set); ldapServers.Len() > 0 {
+		if err := syncUserLdapServers(clientv2, returnstruc.Id, []string{}, expandStringSet(ldapServers)); err != nil {
+			return diag.FromErr(err)
 		}
 	}
 
-	return resourceUserRead(d, m)
-}
+	var diags diag.Diagnostics
+	if d.Get("activation_email").(string) != activationEmailSkip {
+		diags = append(diags, sendUserEmail(ctx, clientv2, returnstruc.Id, activationEmailType)...)
+	}
 
-func resourceUserRead(d *schema.ResourceData, m interface{}) error {
-	configv1 := convertV2toV1Config(m.(*jcapiv2.Configuration))
-	client := jcapiv1.NewAPIClient(configv1)
+	return append(diags, resourceUserRead(ctx, d, m)...)
+}
 
-	res, _, err := client.SystemusersApi.SystemusersGet(context.TODO(),
-		d.Id(), "", "", nil)
+func resourceUserRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	res, ok, err := userReadHelper(m.(*jcapiv2.Configuration), d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
 
 	// If the object does not exist in our infrastructure, we unset the ID
-	// Unfortunately, the http request returns 200 even if the resource does not exist
-	if err != nil {
-		if err.Error() == "EOF" || strings.Contains(err.Error(), "not found") {
-			d.SetId("")
-			return nil
-		}
-		return err
+	// Unfortunately, the http request may return 200 even if the resource does not exist
+	if !ok || res == nil || res.Id == "" {
+		d.SetId("")
+		return nil
 	}
 
 	d.SetId(res.Id)
 
 	if err := d.Set("username", res.Username); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	if err := d.Set("email", res.Email); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	if err := d.Set("firstname", res.Firstname); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	if err := d.Set("lastname", res.Lastname); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	if err := d.Set("display_name", res.Displayname); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	if err := d.Set("enable_mfa", res.EnableUserPortalMultifactor); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	if err := d.Set("ldap_binding_user", res.LdapBindingUser); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	if err := d.Set("password_never_expires", res.PasswordNeverExpires); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	if err := d.Set("sudo", res.Sudo); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	if err := d.Set("suspended", res.Suspended); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	if err := d.Set("phone_number", flattenPhoneNumbers(res.PhoneNumbers)); err != nil {
-		return err
+		return diag.FromErr(err)
+	}
+	if err := d.Set("activated", res.Activated); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("externally_managed", res.ExternallyManaged); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("external_source_type", res.ExternalSourceType); err != nil {
+		return diag.FromErr(err)
+	}
+	restrictedFields := flattenRestrictedFields(res.RestrictedFields)
+	if err := d.Set("restricted_fields", restrictedFields); err != nil {
+		return diag.FromErr(err)
 	}
 
 	// Fetch user's group memberships using v2 API
@@ -230,23 +829,63 @@ func resourceUserRead(d *schema.ResourceData, m interface{}) error {
 	clientv2 := jcapiv2.NewAPIClient(configv2)
 	groupIDs, err := getUserGroupIDs(clientv2, d.Id())
 	if err != nil {
-		return err
+		return diag.FromErr(err)
+	}
+	if d.Get("groups_mode").(string) == groupsModeAdditive {
+		// Groups assigned by other resources or automation are ignored
+		groupIDs = filterManagedGroupIDs(groupIDs, d.Get("managed_groups").(*schema.Set))
 	}
 	if err := d.Set("groups", groupIDs); err != nil {
-		return err
+		return diag.FromErr(err)
+	}
+	if err := readUserGroupNames(d, clientv2, groupIDs); err != nil {
+		return diag.FromErr(err)
 	}
 
-	return nil
+	ldapServers, err := readUserLdapServers(m.(*jcapiv2.Configuration), d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	// Bindings made by jumpcloud_user_association resources are ignored
+	ldapServers = intersectIDs(ldapServers, expandStringSet(d.Get("ldap_servers").(*schema.Set)))
+	if err := d.Set("ldap_servers", ldapServers); err != nil {
+		return diag.FromErr(err)
+	}
+
+	var diags diag.Diagnostics
+	if restricted := restrictedUserAttributes(restrictedFields); len(restricted) > 0 {
+		attrs := sortedRestrictedAttributes(restricted)
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Warning,
+			Summary:  fmt.Sprintf("User %s has attributes owned by %s", res.Username, externalSourceName(res.ExternalSourceType)),
+			Detail: fmt.Sprintf("The following attributes are restricted and will not be updated by Terraform: %s",
+				strings.Join(attrs, ", ")),
+		})
+	}
+
+	return diags
 }
 
-func resourceUserUpdate(d *schema.ResourceData, m interface{}) error {
+func resourceUserUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	configv1 := convertV2toV1Config(m.(*jcapiv2.Configuration))
 	client := jcapiv1.NewAPIClient(configv1)
+	clientv2 := jcapiv2.NewAPIClient(m.(*jcapiv2.Configuration))
+
+	groupsChanged := d.HasChanges("groups", "group_names")
+	var newGroupIDs []string
+	var groupNameIDs map[string]string
+	if groupsChanged {
+		var err error
+		newGroupIDs, groupNameIDs, err = desiredUserGroupIDs(d, clientv2)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
 
 	var phoneNumbers []jcapiv1.SystemuserputPhoneNumbers
 	phoneNumbersRaw, _ := json.Marshal(expandPhoneNumbers(d.Get("phone_number").([]interface{})))
 	if err := json.Unmarshal(phoneNumbersRaw, &phoneNumbers); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	payload := jcapiv1.Systemuserput{
@@ -256,7 +895,7 @@ func resourceUserUpdate(d *schema.ResourceData, m interface{}) error {
 		Lastname:                    d.Get("lastname").(string),
 		Password:                    d.Get("password").(string),
 		EnableUserPortalMultifactor: d.Get("enable_mfa").(bool),
-		LdapBindingUser:             d.Get("ldap_binding_user").(bool),
+		LdapBindingUser:             ldapBindingUser(d),
 		Sudo:                        d.Get("sudo").(bool),
 		Suspended:                   d.Get("suspended").(bool),
 		PasswordNeverExpires:        d.Get("password_never_expires").(bool),
@@ -275,21 +914,21 @@ func resourceUserUpdate(d *schema.ResourceData, m interface{}) error {
 		}
 	}
 
+	// Fields owned by an external system are left as they are
+	omitRestrictedUserFields(&payload, restrictedUserAttributes(d.Get("restricted_fields").(*schema.Set).List()))
+
 	req := map[string]interface{}{
 		"body": payload,
 	}
-	_, _, err := client.SystemusersApi.SystemusersPut(context.TODO(),
+	_, _, err := client.SystemusersApi.SystemusersPut(ctx,
 		d.Id(), "", "", req)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	// Sync group memberships if groups field has changed
-	if d.HasChange("groups") {
-		configv2 := m.(*jcapiv2.Configuration)
-		clientv2 := jcapiv2.NewAPIClient(configv2)
-
-		oldGroups, newGroups := d.GetChange("groups")
+	if groupsChanged {
+		oldGroups, _ := d.GetChange("groups")
 
 		oldGroupsSet := oldGroups.(*schema.Set)
 		oldGroupIDs := make([]string, oldGroupsSet.Len())
@@ -297,29 +936,52 @@ func resourceUserUpdate(d *schema.ResourceData, m interface{}) error {
 			oldGroupIDs[i] = groupID.(string)
 		}
 
-		newGroupsSet := newGroups.(*schema.Set)
-		newGroupIDs := make([]string, newGroupsSet.Len())
-		for i, groupID := range newGroupsSet.List() {
-			newGroupIDs[i] = groupID.(string)
+		if err := syncUserGroups(clientv2, d.Id(), oldGroupIDs, newGroupIDs, groupNameIDs); err != nil {
+			return diag.FromErr(err)
 		}
 
-		if err := syncUserGroups(clientv2, d.Id(), oldGroupIDs, newGroupIDs); err != nil {
-			return err
+		if err := d.Set("group_name_ids", groupNameIDs); err != nil {
+			return diag.FromErr(err)
 		}
+		if err := d.Set("managed_groups", newGroupIDs); err != nil {
+			return diag.FromErr(err)
+		}
+		if !isConfigured(d, "group_names") {
+			// Stop resolving names on read once the configuration uses IDs again
+			if err := d.Set("group_names", []string{}); err != nil {
+				return diag.FromErr(err)
+			}
+		}
+	}
+
+	if d.HasChange("ldap_servers") {
+		oldServers, newServers := d.GetChange("ldap_servers")
+		if err := syncUserLdapServers(clientv2, d.Id(),
+			expandStringSet(oldServers.(*schema.Set)), expandStringSet(newServers.(*schema.Set))); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	var diags diag.Diagnostics
+	if shouldResendActivationEmail(d) {
+		diags = append(diags, sendUserEmail(ctx, clientv2, d.Id(), activationEmailType)...)
+	}
+	if d.HasChange("reset_password_trigger") && d.Get("reset_password_trigger").(string) != "" {
+		diags = append(diags, sendUserEmail(ctx, clientv2, d.Id(), passwordResetEmailType)...)
 	}
 
-	return resourceUserRead(d, m)
+	return append(diags, resourceUserRead(ctx, d, m)...)
 }
 
-func resourceUserDelete(d *schema.ResourceData, m interface{}) error {
+func resourceUserDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	configv1 := convertV2toV1Config(m.(*jcapiv2.Configuration))
 	client := jcapiv1.NewAPIClient(configv1)
 
-	res, _, err := client.SystemusersApi.SystemusersDelete(context.TODO(),
+	res, _, err := client.SystemusersApi.SystemusersDelete(ctx,
 		d.Id(), "", headerAccept, nil)
 	if err != nil {
 		// TODO: sort out error essentials
-		return fmt.Errorf("error deleting user group:%s; response = %+v", err, res)
+		return diag.Errorf("error deleting user group:%s; response = %+v", err, res)
 	}
 	d.SetId("")
 	return nil
@@ -0,0 +1,146 @@
+package jumpcloud
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	jcapiv2 "github.com/TheJumpCloud/jcapi-go/v2"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceUserAssociation() *schema.Resource {
+	return &schema.Resource{
+		Description: "Provides a resource for associating a JumpCloud user directly to objects like SSO applications, G Suite, Office 365, LDAP, and RADIUS servers.",
+		Create:      resourceUserAssociationCreate,
+		Read:        resourceUserAssociationRead,
+		Delete:      resourceUserAssociationDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceUserAssociationImport,
+		},
+		Schema: map[string]*schema.Schema{
+			"user_id": {
+				Description: "The ID of the `jumpcloud_user` resource.",
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+			},
+			"object_id": {
+				Description: "The ID of the object to associate with the user.",
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+			},
+			"type": {
+				Description: "The type of the object to associate with the given user. Possible values: `active_directory`, `application`, `g_suite`, `ldap_server`, `office_365`, `radius_server`.",
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				ValidateFunc: validation.StringInSlice([]string{
+					"active_directory",
+					"application",
+					"g_suite",
+					"ldap_server",
+					"office_365",
+					"radius_server",
+				}, false),
+			},
+		},
+	}
+}
+
+func modifyUserAssociation(client *jcapiv2.APIClient, d *schema.ResourceData, action string) error {
+	payload := jcapiv2.UserGraphManagementReq{
+		Op:    action,
+		Type_: d.Get("type").(string),
+		Id:    d.Get("object_id").(string),
+	}
+
+	req := map[string]interface{}{
+		"body": payload,
+	}
+
+	_, err := client.UsersApi.GraphUserAssociationsPost(
+		context.TODO(), d.Get("user_id").(string), "", "", req)
+
+	return err
+}
+
+func resourceUserAssociationCreate(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*jcapiv2.Configuration)
+	client := jcapiv2.NewAPIClient(config)
+
+	if err := modifyUserAssociation(client, d, "add"); err != nil {
+		return fmt.Errorf("error creating user association: %s", err)
+	}
+
+	d.SetId(fmt.Sprintf("%s/%s/%s", d.Get("user_id").(string), d.Get("object_id").(string), d.Get("type").(string)))
+
+	return resourceUserAssociationRead(d, meta)
+}
+
+func resourceUserAssociationRead(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*jcapiv2.Configuration)
+	client := jcapiv2.NewAPIClient(config)
+
+	userID := d.Get("user_id").(string)
+	objectID := d.Get("object_id").(string)
+	objectType := d.Get("type").(string)
+
+	associations, res, err := listUserAssociations(sdkUserAssociations(client, userID, objectType))
+	if err != nil {
+		if res != nil && res.StatusCode == http.StatusNotFound {
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+
+	for _, v := range associations {
+		if v.To != nil && v.To.Id == objectID {
+			d.SetId(fmt.Sprintf("%s/%s/%s", userID, objectID, objectType))
+			return nil
+		}
+	}
+
+	// Unset the ID to remove the resource from the state
+	d.SetId("")
+	return nil
+}
+
+func resourceUserAssociationDelete(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*jcapiv2.Configuration)
+	client := jcapiv2.NewAPIClient(config)
+
+	if err := modifyUserAssociation(client, d, "remove"); err != nil {
+		return fmt.Errorf("error deleting user association: %s", err)
+	}
+	return nil
+}
+
+func resourceUserAssociationImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	// Expected ID format: <user_id>/<object_id>/<type>
+	idParts := strings.Split(d.Id(), "/")
+	if len(idParts) != 3 {
+		return nil, fmt.Errorf("unexpected format of ID (%s), expected <user_id>/<object_id>/<type>", d.Id())
+	}
+	userID := idParts[0]
+	objectID := idParts[1]
+	objectType := idParts[2]
+
+	if err := d.Set("user_id", userID); err != nil {
+		return nil, err
+	}
+	if err := d.Set("object_id", objectID); err != nil {
+		return nil, err
+	}
+	if err := d.Set("type", objectType); err != nil {
+		return nil, err
+	}
+
+	d.SetId(fmt.Sprintf("%s/%s/%s", userID, objectID, objectType))
+
+	return []*schema.ResourceData{d}, nil
+}
@@ -0,0 +1,53 @@
+package jumpcloud
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestUserAssociationResourceBasic(t *testing.T) {
+	rName := acctest.RandStringFromCharSet(10, acctest.CharSetAlpha)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: nil,
+		Steps: []resource.TestStep{
+			{
+				Config: testUserAssociationResourceConfigBasic(rName),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("jumpcloud_user_association.test_assoc", "user_id"),
+					resource.TestCheckResourceAttrSet("jumpcloud_user_association.test_assoc", "object_id"),
+				),
+			},
+			{
+				ResourceName:      "jumpcloud_user_association.test_assoc",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testUserAssociationResourceConfigBasic(name string) string {
+	return fmt.Sprintf(`
+resource "jumpcloud_application" "test_app" {
+  display_name = "test_app_%[1]s"
+  sso_url      = "https://sso.jumpcloud.com/saml2/test_app_%[1]s"
+}
+
+resource "jumpcloud_user" "test_user" {
+  username = "%[1]s"
+  email    = "%[1]s@testorg.com"
+}
+
+resource "jumpcloud_user_association" "test_assoc" {
+  user_id   = jumpcloud_user.test_user.id
+  object_id = jumpcloud_application.test_app.id
+  type      = "application"
+}
+`, name)
+}
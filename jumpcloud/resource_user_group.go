@@ -3,22 +3,307 @@ package jumpcloud
 import (
 	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
+	"io"
 	"net/http"
-	"slices"
+	"regexp"
+	"sort"
 	"strings"
 
 	jcapiv2 "github.com/TheJumpCloud/jcapi-go/v2"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
+const (
+	membershipMethodStatic           = "static"
+	membershipMethodDynamicReview    = "dynamic_review"
+	membershipMethodDynamicAutomatic = "dynamic_automatic"
+)
+
+// membershipMethods maps the membership_method values to the JumpCloud ones
+var membershipMethods = map[string]string{
+	membershipMethodStatic:           "STATIC",
+	membershipMethodDynamicReview:    "DYNAMIC_REVIEW_REQUIRED",
+	membershipMethodDynamicAutomatic: "DYNAMIC_AUTOMATED",
+}
+
+// radiusReplyAttributeNames are the attributes of the standard RADIUS
+// dictionary (RFC 2865, 2868, 2869, 3162 and 4818) allowed in Access-Accept
+var radiusReplyAttributeNames = []string{
+	"Acct-Interim-Interval",
+	"Callback-Id",
+	"Callback-Number",
+	"Class",
+	"Delegated-IPv6-Prefix",
+	"Filter-Id",
+	"Framed-AppleTalk-Link",
+	"Framed-AppleTalk-Network",
+	"Framed-AppleTalk-Zone",
+	"Framed-Compression",
+	"Framed-IP-Address",
+	"Framed-IP-Netmask",
+	"Framed-IPv6-Pool",
+	"Framed-IPv6-Prefix",
+	"Framed-IPv6-Route",
+	"Framed-IPX-Network",
+	"Framed-Interface-Id",
+	"Framed-MTU",
+	"Framed-Pool",
+	"Framed-Protocol",
+	"Framed-Route",
+	"Framed-Routing",
+	"Idle-Timeout",
+	"Login-IP-Host",
+	"Login-IPv6-Host",
+	"Login-LAT-Group",
+	"Login-LAT-Node",
+	"Login-LAT-Port",
+	"Login-LAT-Service",
+	"Login-Service",
+	"Login-TCP-Port",
+	"Port-Limit",
+	"Reply-Message",
+	"Service-Type",
+	"Session-Timeout",
+	"Termination-Action",
+	"Tunnel-Assignment-Id",
+	"Tunnel-Client-Auth-Id",
+	"Tunnel-Client-Endpoint",
+	"Tunnel-Medium-Type",
+	"Tunnel-Password",
+	"Tunnel-Preference",
+	"Tunnel-Private-Group-Id",
+	"Tunnel-Server-Auth-Id",
+	"Tunnel-Server-Endpoint",
+	"Tunnel-Type",
+}
+
+// posixGroupNamePattern matches valid POSIX group names
+var posixGroupNamePattern = regexp.MustCompile(`^[a-z_][a-z0-9_-]{0,31}$`)
+
 func resourceUserGroup() *schema.Resource {
 	return &schema.Resource{
 		Create: resourceUserGroupCreate,
 		Read:   resourceUserGroupRead,
 		Update: resourceUserGroupUpdate,
 		Delete: resourceUserGroupDelete,
+		CustomizeDiff: customdiff.All(
+			customizeUserGroupPosixGroups,
+			validateUserGroupAttributes,
+			validateUserGroupMembershipMethod,
+			customizeMemberAlternatives("members", "member_ids"),
+		),
+		SchemaVersion: 2,
+		StateUpgraders: []schema.StateUpgrader{
+			{
+				Type:    resourceUserGroupV0().CoreConfigSchema().ImpliedType(),
+				Upgrade: resourceUserGroupStateUpgradeV0,
+				Version: 0,
+			},
+			{
+				Type:    resourceUserGroupV1().CoreConfigSchema().ImpliedType(),
+				Upgrade: resourceUserGroupStateUpgradeV1,
+				Version: 1,
+			},
+		},
+		Schema: userGroupSchema(),
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+	}
+}
+
+func userGroupSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"name": {
+			Type:     schema.TypeString,
+			Required: true,
+		},
+		"description": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "Description of the group, e.g. its owner",
+		},
+		"email": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "E-mail address of the group, e.g. of its owner",
+		},
+		"attributes": {
+			Type:     schema.TypeList,
+			Optional: true,
+			Computed: true,
+			MaxItems: 1,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"posix_group": {
+						Type:        schema.TypeList,
+						Optional:    true,
+						Description: "POSIX groups of the user group. The ID cannot be changed after creation",
+						Elem: &schema.Resource{
+							Schema: map[string]*schema.Schema{
+								"id": {
+									Type:         schema.TypeInt,
+									Required:     true,
+									Description:  "The GID of the POSIX group",
+									ValidateFunc: validation.IntBetween(1, 65535),
+								},
+								"name": {
+									Type:        schema.TypeString,
+									Required:    true,
+									Description: "The name of the POSIX group",
+									ValidateFunc: validation.StringMatch(posixGroupNamePattern,
+										"must start with a lowercase letter or underscore, followed by at most 31 "+
+											"lowercase letters, digits, underscores or dashes"),
+								},
+							},
+						},
+					},
+					"ldap_groups": {
+						Type:     schema.TypeList,
+						Optional: true,
+						MaxItems: 1,
+						Description: "The name of the group in the JumpCloud LDAP directory. " +
+							"Setting it enables LDAP sync of the group",
+						Elem: &schema.Schema{
+							Type:         schema.TypeString,
+							ValidateFunc: validation.StringIsNotWhiteSpace,
+						},
+					},
+					// From the JumpCloud UI: Samba Authentication must be
+					// configured in the JumpCloud LDAP Directory and LDAP
+					// sync must be enabled on this group before Samba
+					// Authentication can be enabled.
+					"enable_samba": {
+						Type:        schema.TypeBool,
+						Optional:    true,
+						Description: "Enables Samba authentication for the members. Requires ldap_groups",
+					},
+					"radius_reply_attribute": {
+						Type:        schema.TypeList,
+						Optional:    true,
+						Description: "RADIUS attributes JumpCloud RADIUS returns for the members, e.g. for VLAN assignment",
+						Elem: &schema.Resource{
+							Schema: map[string]*schema.Schema{
+								"name": {
+									Type:         schema.TypeString,
+									Required:     true,
+									Description:  "The name of the attribute in the standard RADIUS dictionary, e.g. Tunnel-Private-Group-Id",
+									ValidateFunc: validation.StringInSlice(radiusReplyAttributeNames, false),
+								},
+								"value": {
+									Type:        schema.TypeString,
+									Required:    true,
+									Description: "The value of the attribute",
+								},
+							},
+						},
+					},
+					"sudo": {
+						Type:        schema.TypeList,
+						Optional:    true,
+						MaxItems:    1,
+						Description: "Grants the members admin rights on the systems bound to the group",
+						Elem: &schema.Resource{
+							Schema: map[string]*schema.Schema{
+								"enabled": {
+									Type:        schema.TypeBool,
+									Required:    true,
+									Description: "Whether the members are admins",
+								},
+								"without_password": {
+									Type:        schema.TypeBool,
+									Optional:    true,
+									Description: "Whether the members can sudo without a password. Requires enabled",
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		"membership_method": {
+			Type:     schema.TypeString,
+			Optional: true,
+			Default:  membershipMethodStatic,
+			Description: "Either static, for members managed explicitly, dynamic_review, for members " +
+				"suggested by member_query and approved by an admin, or dynamic_automatic, for members " +
+				"computed from member_query",
+			ValidateFunc: validation.StringInSlice([]string{
+				membershipMethodStatic,
+				membershipMethodDynamicReview,
+				membershipMethodDynamicAutomatic,
+			}, false),
+		},
+		"member_query": {
+			Type:        schema.TypeList,
+			Optional:    true,
+			MaxItems:    1,
+			Description: "The query selecting the members of a dynamic group",
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"filter": {
+						Type:        schema.TypeList,
+						Required:    true,
+						MinItems:    1,
+						Description: "Filters all members have to match",
+						Elem: &schema.Resource{
+							Schema: map[string]*schema.Schema{
+								"field": {
+									Type:        schema.TypeString,
+									Required:    true,
+									Description: "The user field to filter on, e.g. department or location",
+								},
+								"operator": {
+									Type:        schema.TypeString,
+									Required:    true,
+									Description: "The comparison operator",
+									ValidateFunc: validation.StringInSlice([]string{
+										"eq", "ne", "in", "gt", "ge", "lt", "le",
+									}, false),
+								},
+								"value": {
+									Type:        schema.TypeString,
+									Required:    true,
+									Description: "The value to compare the field with",
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		"members": {
+			Type:          schema.TypeSet,
+			Optional:      true,
+			Computed:      true,
+			ConflictsWith: []string{"member_ids"},
+			Description: "The emails of the members, compared case-insensitively. " +
+				"Conflicts with member_ids. Computed for dynamic groups",
+			Elem: &schema.Schema{
+				Type: schema.TypeString,
+			},
+			Set: hashStringIgnoringCase,
+		},
+		"member_ids": {
+			Type:          schema.TypeSet,
+			Optional:      true,
+			Computed:      true,
+			ConflictsWith: []string{"members"},
+			Description:   "The IDs of the members. Conflicts with members. Computed for dynamic groups",
+			Elem: &schema.Schema{
+				Type: schema.TypeString,
+			},
+		},
+	}
+}
+
+// resourceUserGroupV0 is the schema of jumpcloud_user_group before posix
+// groups were configured as blocks
+func resourceUserGroupV0() *schema.Resource {
+	return &schema.Resource{
 		Schema: map[string]*schema.Schema{
 			"name": {
 				Type:     schema.TypeString,
@@ -33,46 +318,253 @@ func resourceUserGroup() *schema.Resource {
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
 						"posix_groups": {
-							Type: schema.TypeString,
-							// PosixGroups cannot be edited after group creation.
+							Type:     schema.TypeString,
 							ForceNew: true,
 							Optional: true,
 						},
-						// enable_samba has a more complicated lifecycle,
-						// Commenting out for now as it is ignored in CRU by the JCAPI
-						// From Jumpcloud UI:
-						// Samba Authentication must be configured in the
-						// JumpCloud LDAP Directory and LDAP sync must be enabled
-						// on this group before Samba Authentication can be enabled.
-						// "enable_samba": {
-						// 	Type:     schema.TypeBool,
-						// 	Optional: true,
-						// },
 					},
 				},
 			},
 			"members": {
-				Type:             schema.TypeList,
-				Optional:         true,
-				Computed:         true,
-				DiffSuppressFunc: EqualIgnoringOrder,
-				Description:      "This is a set of user emails associated with this group",
+				Type:     schema.TypeList,
+				Optional: true,
+				Computed: true,
 				Elem: &schema.Schema{
 					Type: schema.TypeString,
 				},
 			},
 		},
-		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+	}
+}
+
+// resourceUserGroupStateUpgradeV0 converts the "id:name,id:name" posix_groups
+// string to posix_group blocks
+func resourceUserGroupStateUpgradeV0(_ context.Context, rawState map[string]interface{}, _ interface{}) (map[string]interface{}, error) {
+	attributes, ok := rawState["attributes"].([]interface{})
+	if !ok {
+		return rawState, nil
+	}
+	for i, a := range attributes {
+		attr, ok := a.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		posixStr, _ := attr["posix_groups"].(string)
+		delete(attr, "posix_groups")
+		attr["posix_group"] = parsePosixGroupsString(posixStr)
+		attributes[i] = attr
+	}
+	rawState["attributes"] = attributes
+	return rawState, nil
+}
+
+// resourceUserGroupV1 is the schema of jumpcloud_user_group before members
+// became a set and member_ids was added
+func resourceUserGroupV1() *schema.Resource {
+	s := userGroupSchema()
+	delete(s, "member_ids")
+	s["members"] = &schema.Schema{
+		Type:     schema.TypeList,
+		Optional: true,
+		Computed: true,
+		Elem: &schema.Schema{
+			Type: schema.TypeString,
 		},
 	}
+	return &schema.Resource{
+		Schema: s,
+	}
+}
+
+// resourceUserGroupStateUpgradeV1 removes members that only differ in case,
+// as they are the same element of the members set
+func resourceUserGroupStateUpgradeV1(_ context.Context, rawState map[string]interface{}, _ interface{}) (map[string]interface{}, error) {
+	members, ok := rawState["members"].([]interface{})
+	if !ok {
+		return rawState, nil
+	}
+	seen := make(map[string]bool, len(members))
+	unique := make([]interface{}, 0, len(members))
+	for _, m := range members {
+		email, ok := m.(string)
+		if !ok || seen[strings.ToLower(email)] {
+			continue
+		}
+		seen[strings.ToLower(email)] = true
+		unique = append(unique, email)
+	}
+	rawState["members"] = unique
+	return rawState, nil
+}
+
+// customizeUserGroupPosixGroups rejects duplicate GIDs and recreates the group
+// when the GID of an existing posix group changes, as JumpCloud only allows
+// to rename posix groups
+func customizeUserGroupPosixGroups(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+	oldAttr, newAttr := d.GetChange("attributes")
+
+	newGroups := expandPosixGroups(posixGroupsOf(newAttr))
+	seen := make(map[int32]bool)
+	for _, g := range newGroups {
+		if seen[g.Id] {
+			return fmt.Errorf("posix group id %d is used more than once", g.Id)
+		}
+		seen[g.Id] = true
+	}
+
+	if d.Id() == "" || !d.HasChange("attributes") {
+		return nil
+	}
+	oldGroups := expandPosixGroups(posixGroupsOf(oldAttr))
+	if len(oldGroups) == 0 {
+		return nil
+	}
+	if len(oldGroups) != len(newGroups) {
+		return d.ForceNew("attributes")
+	}
+	for i := range oldGroups {
+		if oldGroups[i].Id != newGroups[i].Id {
+			return d.ForceNew("attributes")
+		}
+	}
+	return nil
+}
+
+// validateUserGroupAttributes enforces the order in which JumpCloud allows to
+// enable the attributes: Samba requires LDAP sync, passwordless sudo requires
+// sudo
+func validateUserGroupAttributes(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+	attr, ok := expandAttributes(d.Get("attributes"))
+	if !ok {
+		return nil
+	}
+	if attr.SambaEnabled && len(attr.LdapGroups) == 0 {
+		return fmt.Errorf("attributes.enable_samba requires LDAP sync, i.e. attributes.ldap_groups")
+	}
+	if attr.Sudo != nil && attr.Sudo.WithoutPassword && !attr.Sudo.Enabled {
+		return fmt.Errorf("attributes.sudo.without_password requires attributes.sudo.enabled")
+	}
+	return nil
+}
+
+// validateUserGroupMembershipMethod rejects members of dynamic groups, as
+// JumpCloud computes them from the member query
+func validateUserGroupMembershipMethod(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+	if d.Get("membership_method").(string) == membershipMethodStatic {
+		return nil
+	}
+
+	for _, key := range []string{"members", "member_ids"} {
+		if diffConfigured(d, key) {
+			return fmt.Errorf("%s cannot be configured with membership_method %q, "+
+				"the members of dynamic groups are computed from member_query", key, d.Get("membership_method").(string))
+		}
+	}
+	if len(d.Get("member_query").([]interface{})) == 0 {
+		return fmt.Errorf("membership_method %q requires a member_query", d.Get("membership_method").(string))
+	}
+	return nil
+}
+
+// customizeMemberAlternatives returns a CustomizeDiff function marking the
+// alternative to the configured attribute of member emails or IDs as changing
+// with it. Emails only differing in case aren't a change.
+func customizeMemberAlternatives(emailsKey, idsKey string) schema.CustomizeDiffFunc {
+	return func(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+		if emailsChanged(d, emailsKey) && !diffConfigured(d, idsKey) {
+			if err := d.SetNewComputed(idsKey); err != nil {
+				return err
+			}
+		}
+		if d.HasChange(idsKey) && !diffConfigured(d, emailsKey) {
+			return d.SetNewComputed(emailsKey)
+		}
+		return nil
+	}
+}
+
+// emailsChanged reports whether a set of emails changed, ignoring case.
+// HasChange compares the raw values, so it reports a change for emails
+// configured in another case than they are stored.
+func emailsChanged(d *schema.ResourceDiff, key string) bool {
+	if !d.HasChange(key) {
+		return false
+	}
+	o, n := d.GetChange(key)
+	oldSet, newSet := o.(*schema.Set), n.(*schema.Set)
+	if oldSet.Len() != newSet.Len() {
+		return true
+	}
+	for _, email := range newSet.List() {
+		if !oldSet.Contains(email) {
+			return true
+		}
+	}
+	return false
+}
+
+// diffConfigured is like isConfigured for a ResourceDiff
+func diffConfigured(d *schema.ResourceDiff, key string) bool {
+	config := d.GetRawConfig()
+	if config.IsNull() || !config.IsKnown() {
+		return false
+	}
+	return !config.GetAttr(key).IsNull()
+}
+
+// configuredMemberIDs returns the IDs of the configured members, resolving
+// the emails unless the IDs attribute is configured
+func configuredMemberIDs(config *jcapiv2.Configuration, d *schema.ResourceData, emailsKey, idsKey string) ([]string, error) {
+	if isConfigured(d, idsKey) {
+		return expandStringSet(d.Get(idsKey).(*schema.Set)), nil
+	}
+	idsByEmail, err := userEmailsToIDs(config, expandStringSet(d.Get(emailsKey).(*schema.Set)))
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, 0, len(idsByEmail))
+	for _, id := range idsByEmail {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
+
+// flattenMembershipMethod converts a JumpCloud membership method to the
+// membership_method value
+func flattenMembershipMethod(method string) string {
+	for k, v := range membershipMethods {
+		if v == method {
+			return k
+		}
+	}
+	return membershipMethodStatic
+}
+
+// posixGroupsOf returns the posix_group list of an attributes value
+func posixGroupsOf(attr interface{}) interface{} {
+	list, ok := attr.([]interface{})
+	if !ok || len(list) == 0 {
+		return nil
+	}
+	mapAttr, ok := list[0].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	return mapAttr["posix_group"]
 }
 
 func resourceUserGroupCreate(d *schema.ResourceData, m interface{}) error {
 	config := m.(*jcapiv2.Configuration)
 	client := jcapiv2.NewAPIClient(config)
 
-	body := jcapiv2.UserGroupPost{Name: d.Get("name").(string)}
+	body := UserGroupPost{
+		Name:             d.Get("name").(string),
+		Description:      d.Get("description").(string),
+		Email:            d.Get("email").(string),
+		MemberQuery:      expandMemberQuery(d.Get("member_query")),
+		MembershipMethod: membershipMethods[d.Get("membership_method").(string)],
+	}
 
 	// For Attributes.PosixGroups, only the first member of the slice
 	// is considered by the JCAPI
@@ -80,29 +572,21 @@ func resourceUserGroupCreate(d *schema.ResourceData, m interface{}) error {
 		body.Attributes = attr
 	}
 
-	req := map[string]interface{}{
-		"body": body,
-	}
-	group, res, err := client.UserGroupsApi.GroupsUserPost(context.TODO(),
-		"", headerAccept, req)
-	if err != nil {
-		// TODO: sort out error essentials
-		return fmt.Errorf("error creating user group %s: %s - response = %+v",
-			(req["body"].(jcapiv2.UserGroupPost)).Name, err, res)
+	// The SDK's UserGroupPost lacks the description and email
+	var group UserGroup
+	if err := doAPIRequest(config, http.MethodPost, config.BasePath+"/usergroups", body, &group); err != nil {
+		return fmt.Errorf("error creating user group %s: %s", body.Name, err)
 	}
 
-	d.SetId(group.Id)
+	d.SetId(group.ID)
 
-	memberIds, err := userEmailsToIDs(config, d.Get("members").([]interface{}))
+	memberIds, err := configuredMemberIDs(config, d, "members", "member_ids")
 	if err != nil {
 		return err
 	}
 
-	for _, memberId := range memberIds {
-		err := manageGroupMember(client, d, memberId, "add")
-		if err != nil {
-			return err
-		}
+	if err := syncGroupMembers(client, d.Id(), group.Name, []string{}, memberIds); err != nil {
+		return err
 	}
 	return resourceUserGroupRead(d, m)
 }
@@ -133,20 +617,35 @@ func resourceUserGroupRead(d *schema.ResourceData, m interface{}) error {
 	if err := d.Set("name", group.Name); err != nil {
 		return err
 	}
+	if err := d.Set("description", group.Description); err != nil {
+		return err
+	}
+	if err := d.Set("email", group.Email); err != nil {
+		return err
+	}
 	if err := d.Set("attributes", flattenAttributes(&group.Attributes)); err != nil {
 		return err
 	}
+	if err := d.Set("membership_method", flattenMembershipMethod(group.MembershipMethod)); err != nil {
+		return err
+	}
+	if err := d.Set("member_query", flattenMemberQuery(group.MemberQuery)); err != nil {
+		return err
+	}
 
 	client := jcapiv2.NewAPIClient(config)
 	memberIDs, err := getUserGroupMemberIDs(client, d.Id())
 	if err != nil {
 		return err
 	}
-	memberEmails, err := userIDsToEmails(config, memberIDs)
+	emails, err := memberEmails(config, memberIDs)
 	if err != nil {
 		return err
 	}
-	if err := d.Set("members", memberEmails); err != nil {
+	if err := d.Set("members", emails); err != nil {
+		return err
+	}
+	if err := d.Set("member_ids", memberIDs); err != nil {
 		return err
 	}
 
@@ -156,19 +655,12 @@ func resourceUserGroupRead(d *schema.ResourceData, m interface{}) error {
 func userGroupReadHelper(config *jcapiv2.Configuration, id string) (ug *UserGroup,
 	ok bool, err error) {
 
-	req, err := http.NewRequest(http.MethodGet,
+	req, err := newAPIRequest(config, http.MethodGet,
 		config.BasePath+"/usergroups/"+id, nil)
 	if err != nil {
 		return
 	}
 
-	req.Header.Add("x-api-key", config.DefaultHeader["x-api-key"])
-	if config.DefaultHeader["x-org-id"] != "" {
-		req.Header.Add("x-org-id", config.DefaultHeader["x-org-id"])
-	}
-	req.Header.Add("Content-Type", "application/json")
-	req.Header.Add("Accept", "application/json")
-
 	res, err := http.DefaultClient.Do(req)
 	if err != nil {
 		return
@@ -179,61 +671,130 @@ func userGroupReadHelper(config *jcapiv2.Configuration, id string) (ug *UserGrou
 		return
 	}
 
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return
+	}
+
 	ok = true
-	err = json.NewDecoder(res.Body).Decode(&ug)
+	if err = json.Unmarshal(body, &ug); err != nil || ug == nil {
+		return
+	}
+	var raw struct {
+		Attributes map[string]interface{} `json:"attributes"`
+	}
+	if err = json.Unmarshal(body, &raw); err != nil {
+		return
+	}
+	ug.RawAttributes = raw.Attributes
 	return
 }
 
-func resourceUserGroupUpdate(d *schema.ResourceData, m interface{}) error {
-	config := m.(*jcapiv2.Configuration)
-	client := jcapiv2.NewAPIClient(config)
-
-	body := jcapiv2.UserGroupPost{Name: d.Get("name").(string)}
-	if attr, ok := expandAttributes(d.Get("attributes")); ok {
-		body.Attributes = attr
-	} else {
-		return errors.New("unable to update, attributes not expandable")
+// userGroupPatchBody returns the fields of the user group to send on update.
+// The attributes are merged into the current ones, so that attributes the
+// resource doesn't manage are preserved.
+func userGroupPatchBody(d *schema.ResourceData, current map[string]interface{}) map[string]interface{} {
+	body := map[string]interface{}{
+		"name": d.Get("name").(string),
 	}
-
-	req := map[string]interface{}{
-		"body": body,
+	for _, key := range []string{"description", "email"} {
+		if d.HasChange(key) {
+			body[key] = d.Get(key).(string)
+		}
 	}
-	// behaves like PUT, will fail if
-	// attributes.posixGroups isn't sent, see GODOC
-	_, res, err := client.UserGroupsApi.GroupsUserPatch(context.TODO(),
-		d.Id(), "", headerAccept, req)
-	if err != nil {
-		// TODO: sort out error essentials
-		return fmt.Errorf("error deleting user group:%s; response = %+v", err, res)
+	// Groups created before membership_method existed are static already
+	if o, n := d.GetChange("membership_method"); o != n && (o != "" || n != membershipMethodStatic) {
+		body["membershipMethod"] = membershipMethods[n.(string)]
+	}
+	if d.HasChange("member_query") {
+		body["memberQuery"] = expandMemberQuery(d.Get("member_query"))
 	}
 
-	oldMemberIDs, err := getUserGroupMemberIDs(client, d.Id())
-	if err != nil {
-		return err
+	attributes := make(map[string]interface{}, len(current))
+	for k, v := range current {
+		attributes[k] = v
+	}
+	attr, ok := expandAttributes(d.Get("attributes"))
+	if !ok {
+		attr = &UserGroupAttributes{}
+	}
+	if len(attr.PosixGroups) > 0 {
+		attributes["posixGroups"] = attr.PosixGroups
+	}
+	// The other attributes are only sent when they change, the current
+	// values are kept otherwise
+	if d.HasChange("attributes.0.ldap_groups") {
+		ldapGroups := attr.LdapGroups
+		if ldapGroups == nil {
+			ldapGroups = []UserGroupLdapGroup{}
+		}
+		attributes["ldapGroups"] = ldapGroups
+	}
+	if d.HasChange("attributes.0.enable_samba") {
+		attributes["sambaEnabled"] = attr.SambaEnabled
+	}
+	if d.HasChange("attributes.0.sudo") {
+		sudo := attr.Sudo
+		if sudo == nil {
+			sudo = &UserGroupSudo{}
+		}
+		attributes["sudo"] = sudo
+	}
+	if d.HasChange("attributes.0.radius_reply_attribute") {
+		radius := attr.Radius
+		if radius == nil {
+			radius = &UserGroupRadius{Reply: []UserGroupRadiusReply{}}
+		}
+		attributes["radius"] = radius
+	}
+	if len(attributes) > 0 {
+		body["attributes"] = attributes
 	}
 
-	newMemberIDs, err := userEmailsToIDs(config, d.Get("members").([]interface{}))
-	if err != nil {
-		return err
+	return body
+}
+
+// userGroupPatchHelper consumes the JC's HTTP API directly, as the SDK
+// can't send a partial body
+func userGroupPatchHelper(config *jcapiv2.Configuration, id string, body map[string]interface{}) error {
+	if err := doAPIRequest(config, http.MethodPatch, config.BasePath+"/usergroups/"+id, body, nil); err != nil {
+		return fmt.Errorf("error updating user group %s: %s", id, err)
 	}
+	return nil
+}
 
-	//add any new users
-	for _, newMemberID := range newMemberIDs {
-		if !slices.Contains(oldMemberIDs, newMemberID) {
-			err := manageGroupMember(client, d, newMemberID, "add")
-			if err != nil {
-				return err
-			}
+func resourceUserGroupUpdate(d *schema.ResourceData, m interface{}) error {
+	config := m.(*jcapiv2.Configuration)
+	client := jcapiv2.NewAPIClient(config)
+
+	if d.HasChanges("name", "description", "email", "attributes", "membership_method", "member_query") {
+		group, ok, err := userGroupReadHelper(config, d.Id())
+		if err != nil {
+			return err
+		}
+		if !ok || group == nil {
+			return fmt.Errorf("error updating user group %s: not found", d.Id())
+		}
+
+		if err := userGroupPatchHelper(config, d.Id(), userGroupPatchBody(d, group.RawAttributes)); err != nil {
+			return err
 		}
 	}
 
-	//remove any old users
-	for _, oldMemberID := range oldMemberIDs {
-		if !slices.Contains(newMemberIDs, oldMemberID) {
-			err := manageGroupMember(client, d, oldMemberID, "remove")
-			if err != nil {
-				return err
-			}
+	if d.HasChanges("members", "member_ids") {
+		oldMemberIDs, err := getUserGroupMemberIDs(client, d.Id())
+		if err != nil {
+			return err
+		}
+
+		newMemberIDs, err := configuredMemberIDs(config, d, "members", "member_ids")
+		if err != nil {
+			return err
+		}
+
+		// add any new users and remove any old users
+		if err := syncGroupMembers(client, d.Id(), d.Get("name").(string), oldMemberIDs, newMemberIDs); err != nil {
+			return err
 		}
 	}
 
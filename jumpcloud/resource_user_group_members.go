@@ -0,0 +1,193 @@
+package jumpcloud
+
+import (
+	"sort"
+	"strings"
+
+	jcapiv2 "github.com/TheJumpCloud/jcapi-go/v2"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func resourceUserGroupMembers() *schema.Resource {
+	return &schema.Resource{
+		Description: "Manages the members of a JumpCloud user group as a single resource. " +
+			"Unless authoritative is set, members added outside of Terraform are left alone.",
+		Create: resourceUserGroupMembersCreate,
+		Read:   resourceUserGroupMembersRead,
+		Update: resourceUserGroupMembersUpdate,
+		Delete: resourceUserGroupMembersDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceUserGroupMembersImport,
+		},
+		CustomizeDiff: customizeMemberAlternatives("emails", "user_ids"),
+		Schema: map[string]*schema.Schema{
+			"group_id": {
+				Description: "The ID of the `jumpcloud_user_group` resource.",
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+			},
+			"user_ids": {
+				Description:  "The IDs of the members. Conflicts with `emails`, computed from it otherwise.",
+				Type:         schema.TypeSet,
+				Optional:     true,
+				Computed:     true,
+				ExactlyOneOf: []string{"user_ids", "emails"},
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"emails": {
+				Description:  "The emails of the members, compared case-insensitively. Conflicts with `user_ids`, computed from it otherwise.",
+				Type:         schema.TypeSet,
+				Optional:     true,
+				Computed:     true,
+				ExactlyOneOf: []string{"user_ids", "emails"},
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Set: hashStringIgnoringCase,
+			},
+			"authoritative": {
+				Description: "Whether members that aren't listed are removed from the group. " +
+					"Otherwise only the listed members are managed.",
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+		},
+	}
+}
+
+// intersectIDs returns the IDs contained in both a and b, sorted
+func intersectIDs(a, b []string) []string {
+	inB := make(map[string]bool, len(b))
+	for _, id := range b {
+		inB[id] = true
+	}
+	out := []string{}
+	for _, id := range a {
+		if inB[id] {
+			out = append(out, id)
+			delete(inB, id)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// userGroupMembersBaseline returns the members to diff the desired members
+// against. Authoritative groups are diffed against all current members,
+// others only against the previously managed and the desired members, so
+// that members added outside of Terraform are kept.
+func userGroupMembersBaseline(current, managed, desired []string, authoritative bool) []string {
+	if authoritative {
+		return current
+	}
+	return append(intersectIDs(current, managed), intersectIDs(current, desired)...)
+}
+
+func syncUserGroupMembers(d *schema.ResourceData, m interface{}, managed []string) error {
+	config := m.(*jcapiv2.Configuration)
+	client := jcapiv2.NewAPIClient(config)
+	groupID := d.Get("group_id").(string)
+
+	desired, err := configuredMemberIDs(config, d, "emails", "user_ids")
+	if err != nil {
+		return err
+	}
+	current, err := getUserGroupMemberIDs(client, groupID)
+	if err != nil {
+		return err
+	}
+
+	baseline := userGroupMembersBaseline(current, managed, desired, d.Get("authoritative").(bool))
+	if err := syncGroupMembers(client, groupID, "", baseline, desired); err != nil {
+		return err
+	}
+	if err := d.Set("user_ids", desired); err != nil {
+		return err
+	}
+	d.SetId(groupID)
+	return nil
+}
+
+func resourceUserGroupMembersCreate(d *schema.ResourceData, m interface{}) error {
+	if err := syncUserGroupMembers(d, m, nil); err != nil {
+		return err
+	}
+	return resourceUserGroupMembersRead(d, m)
+}
+
+func resourceUserGroupMembersRead(d *schema.ResourceData, m interface{}) error {
+	config := m.(*jcapiv2.Configuration)
+	client := jcapiv2.NewAPIClient(config)
+
+	current, err := getUserGroupMemberIDs(client, d.Id())
+	if err != nil {
+		if strings.Contains(err.Error(), "404") {
+			// Unset the ID to remove the resource from the state
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+
+	members := current
+	if !d.Get("authoritative").(bool) {
+		members = intersectIDs(current, expandStringSet(d.Get("user_ids").(*schema.Set)))
+	}
+	emails, err := memberEmails(config, members)
+	if err != nil {
+		return err
+	}
+
+	if err := d.Set("group_id", d.Id()); err != nil {
+		return err
+	}
+	if err := d.Set("user_ids", members); err != nil {
+		return err
+	}
+	if err := d.Set("emails", emails); err != nil {
+		return err
+	}
+	return nil
+}
+
+func resourceUserGroupMembersUpdate(d *schema.ResourceData, m interface{}) error {
+	managed, _ := d.GetChange("user_ids")
+	if err := syncUserGroupMembers(d, m, expandStringSet(managed.(*schema.Set))); err != nil {
+		return err
+	}
+	return resourceUserGroupMembersRead(d, m)
+}
+
+func resourceUserGroupMembersDelete(d *schema.ResourceData, m interface{}) error {
+	config := m.(*jcapiv2.Configuration)
+	client := jcapiv2.NewAPIClient(config)
+
+	// Only the members in the state are removed, even for authoritative
+	// resources, whose state holds all members as of the last refresh
+	managed := expandStringSet(d.Get("user_ids").(*schema.Set))
+	return syncGroupMembers(client, d.Id(), "", managed, nil)
+}
+
+func resourceUserGroupMembersImport(d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	client := jcapiv2.NewAPIClient(m.(*jcapiv2.Configuration))
+
+	// Expected ID format: <group_id>. All current members become managed.
+	current, err := getUserGroupMemberIDs(client, d.Id())
+	if err != nil {
+		return nil, err
+	}
+	if err := d.Set("group_id", d.Id()); err != nil {
+		return nil, err
+	}
+	if err := d.Set("user_ids", current); err != nil {
+		return nil, err
+	}
+	if err := d.Set("authoritative", false); err != nil {
+		return nil, err
+	}
+	return []*schema.ResourceData{d}, nil
+}
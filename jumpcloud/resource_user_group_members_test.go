@@ -0,0 +1,143 @@
+package jumpcloud
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestUserGroupMembersResourceBasic(t *testing.T) {
+	rName := acctest.RandStringFromCharSet(10, acctest.CharSetAlpha)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testUserGroupMembersResourceConfig(rName, `emails = [jumpcloud_user.test_user_a.email]`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("jumpcloud_user_group_members.test_members", "user_ids.#", "1"),
+					resource.TestCheckTypeSetElemAttrPair("jumpcloud_user_group_members.test_members", "user_ids.*",
+						"jumpcloud_user.test_user_a", "id"),
+				),
+			},
+			{
+				Config: testUserGroupMembersResourceConfig(rName,
+					`user_ids = [jumpcloud_user.test_user_a.id, jumpcloud_user.test_user_b.id]`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("jumpcloud_user_group_members.test_members", "emails.#", "2"),
+					resource.TestCheckTypeSetElemAttr("jumpcloud_user_group_members.test_members", "emails.*",
+						rName+"_b@testorg.com"),
+				),
+			},
+			{
+				ResourceName:      "jumpcloud_user_group_members.test_members",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testUserGroupMembersResourceConfig(name, members string) string {
+	return fmt.Sprintf(`
+		resource "jumpcloud_user" "test_user_a" {
+			username = "%[1]s_a"
+			email    = "%[1]s_a@testorg.com"
+		}
+
+		resource "jumpcloud_user" "test_user_b" {
+			username = "%[1]s_b"
+			email    = "%[1]s_b@testorg.com"
+		}
+
+		resource "jumpcloud_user_group" "test_group" {
+			name = "%[1]s"
+		}
+
+		resource "jumpcloud_user_group_members" "test_members" {
+			group_id = jumpcloud_user_group.test_group.id
+			%[2]s
+		}`, name, members,
+	)
+}
+
+func TestUserGroupMembersBaseline(t *testing.T) {
+	current := []string{"a", "b", "c"}
+
+	testCases := []struct {
+		name          string
+		managed       []string
+		desired       []string
+		authoritative bool
+		added         []string
+		removed       []string
+	}{
+		{"authoritative removes unlisted members", []string{"a"}, []string{"a", "d"}, true,
+			[]string{"d"}, []string{"b", "c"}},
+		{"managed only keeps unlisted members", nil, []string{"a", "d"}, false,
+			[]string{"d"}, nil},
+		{"managed only removes previously managed members", []string{"a", "b"}, []string{"a"}, false,
+			nil, []string{"b"}},
+		{"managed only ignores members removed elsewhere", []string{"a", "e"}, []string{"a"}, false,
+			nil, nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			baseline := userGroupMembersBaseline(current, tc.managed, tc.desired, tc.authoritative)
+			added, removed := diffIDs(baseline, tc.desired)
+			if !reflect.DeepEqual(added, tc.added) {
+				t.Errorf("Expected to add %v, got %v", tc.added, added)
+			}
+			if !reflect.DeepEqual(removed, tc.removed) {
+				t.Errorf("Expected to remove %v, got %v", tc.removed, removed)
+			}
+		})
+	}
+}
+
+func TestIntersectIDs(t *testing.T) {
+	if ids := intersectIDs([]string{"c", "a", "b", "a"}, []string{"a", "c", "d"}); !reflect.DeepEqual(ids, []string{"a", "c"}) {
+		t.Errorf("Expected [a c], got %v", ids)
+	}
+	if ids := intersectIDs(nil, []string{"a"}); ids == nil || len(ids) != 0 {
+		t.Errorf("Expected an empty list, got %v", ids)
+	}
+}
+
+func TestUserGroupMembersEmailsCaseOnlyDiff(t *testing.T) {
+	state := &terraform.InstanceState{
+		ID: "group1",
+		Attributes: map[string]string{
+			"id":            "group1",
+			"group_id":      "group1",
+			"authoritative": "false",
+			"emails.#":      "1",
+			fmt.Sprintf("emails.%d", hashStringIgnoringCase("alice@testorg.com")): "alice@testorg.com",
+			"user_ids.#": "1",
+			fmt.Sprintf("user_ids.%d", schema.HashString("user1")): "user1",
+		},
+	}
+
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{
+		"group_id": "group1",
+		"emails":   []interface{}{"ALICE@testorg.com"},
+	})
+	diff, err := resourceUserGroupMembers().Diff(context.Background(), state, config, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff == nil {
+		return
+	}
+	if _, computed := diff.GetAttribute("user_ids.#"); computed {
+		t.Errorf("Expected no user_ids change for emails only differing in case, got %v", diff)
+	}
+}
@@ -101,20 +101,11 @@ func checkUserGroupMembership(client *jcapiv2.APIClient, groupID, userID string)
 func modifyUserGroupMembership(client *jcapiv2.APIClient,
 	d *schema.ResourceData, action string) error {
 
-	payload := jcapiv2.UserGroupMembersReq{
-		Op:    action,
-		Type_: "user",
-		Id:    d.Get("userid").(string),
-	}
-
-	req := map[string]interface{}{
-		"body": payload,
-	}
-
-	_, err := client.UserGroupMembersMembershipApi.GraphUserGroupMembersPost(
-		context.TODO(), d.Get("groupid").(string), "", "", req)
-
-	return err
+	return applyGroupOperations(postGroupMembership(client), []groupOperation{{
+		groupID: d.Get("groupid").(string),
+		userID:  d.Get("userid").(string),
+		op:      action,
+	}})
 }
 
 func resourceUserGroupMembershipCreate(d *schema.ResourceData, m interface{}) error {
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"net/http"
 	"sort"
 	"strings"
 	"sync"
@@ -12,36 +13,20 @@ import (
 	jcapiv1 "github.com/TheJumpCloud/jcapi-go/v1"
 	jcapiv2 "github.com/TheJumpCloud/jcapi-go/v2"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
-const (
-	// maxConcurrentGroupOps is the maximum number of concurrent group membership operations
-	maxConcurrentGroupOps = 5
-	// groupOpRateLimitMs is the minimum time between operations per worker (rate limiting)
-	groupOpRateLimitMs = 20
-	// maxRetries is the maximum number of retries for API calls
-	maxRetries = 3
-	// baseBackoffMs is the base backoff time in milliseconds for exponential backoff
-	baseBackoffMs = 100
-)
-
-// groupOperation represents a single group membership operation
-type groupOperation struct {
-	groupID   string
-	groupName string
-	op        string // "add" or "remove"
-}
-
 func resourceUserGroupMemberships() *schema.Resource {
 	return &schema.Resource{
 		Description: "Manages all group memberships for a JumpCloud user as a single resource. " +
 			"This resource looks up the user by email and groups by name, then manages " +
 			"the memberships. Use this instead of multiple jumpcloud_user_group_membership " +
 			"resources when you want to manage all of a user's group memberships in one place.",
-		Create: resourceUserGroupMembershipsCreate,
-		Read:   resourceUserGroupMembershipsRead,
-		Update: resourceUserGroupMembershipsUpdate,
-		Delete: resourceUserGroupMembershipsDelete,
+		Create:        resourceUserGroupMembershipsCreate,
+		Read:          resourceUserGroupMembershipsRead,
+		Update:        resourceUserGroupMembershipsUpdate,
+		Delete:        resourceUserGroupMembershipsDelete,
+		CustomizeDiff: customizeUserGroupMembershipsGroups,
 		Schema: map[string]*schema.Schema{
 			"user_email": {
 				Description: "The email address of the JumpCloud user.",
@@ -55,21 +40,55 @@ func resourceUserGroupMemberships() *schema.Resource {
 				Computed:    true,
 			},
 			"groups": {
-				Description: "List of group names that the user should be a member of.",
-				Type:        schema.TypeSet,
-				Required:    true,
+				Description: "Set of names of the user groups the user should be a member of. " +
+					"Names must match exactly one group. Conflicts with `group_id_set`, computed from it otherwise.",
+				Type:         schema.TypeSet,
+				Optional:     true,
+				Computed:     true,
+				ExactlyOneOf: []string{"groups", "group_id_set"},
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"group_id_set": {
+				Description: "Set of IDs of the user groups the user should be a member of. This is the input for group IDs; " +
+					"`group_ids` is output only. Conflicts with `groups`, computed from it otherwise.",
+				Type:         schema.TypeSet,
+				Optional:     true,
+				Computed:     true,
+				ExactlyOneOf: []string{"groups", "group_id_set"},
 				Elem: &schema.Schema{
 					Type: schema.TypeString,
 				},
 			},
+			"mode": {
+				Description: "Either `authoritative`, to remove the user from all groups not listed in `groups`, " +
+					"or `managed_only`, to only manage the listed groups and leave all other memberships alone.",
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  membershipsModeAuthoritative,
+				ValidateFunc: validation.StringInSlice([]string{
+					membershipsModeAuthoritative,
+					membershipsModeManagedOnly,
+				}, false),
+			},
 			"group_ids": {
-				Description: "Map of group names to their IDs (computed).",
+				Description: "Map of group names to their IDs. Output only, set `group_id_set` to reference groups by ID.",
 				Type:        schema.TypeMap,
 				Computed:    true,
 				Elem: &schema.Schema{
 					Type: schema.TypeString,
 				},
 			},
+			"system_groups": {
+				Description: "Set of IDs of system groups the user administers. Only the listed " +
+					"system groups are managed, regardless of `mode`.",
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
 		},
 		Importer: &schema.ResourceImporter{
 			State: userGroupMembershipsImporter,
@@ -77,6 +96,26 @@ func resourceUserGroupMemberships() *schema.Resource {
 	}
 }
 
+// customizeUserGroupMembershipsGroups marks the alternative to the
+// configured groups attribute as changing with it
+func customizeUserGroupMembershipsGroups(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+	if d.HasChange("groups") && !diffConfigured(d, "group_id_set") {
+		if err := d.SetNewComputed("group_id_set"); err != nil {
+			return err
+		}
+		if err := d.SetNewComputed("group_ids"); err != nil {
+			return err
+		}
+	}
+	if d.HasChange("group_id_set") && !diffConfigured(d, "groups") {
+		if err := d.SetNewComputed("groups"); err != nil {
+			return err
+		}
+		return d.SetNewComputed("group_ids")
+	}
+	return nil
+}
+
 func userGroupMembershipsImporter(d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
 	// Import by user email
 	userEmail := d.Id()
@@ -105,13 +144,85 @@ func userGroupMembershipsImporter(d *schema.ResourceData, m interface{}) ([]*sch
 
 // groupLookupResult represents the result of a single group lookup
 type groupLookupResult struct {
-	name  string
-	id    string
-	err   error
+	name string
+	ids  []string
+	err  error
+}
+
+// groupsNotFoundError is returned by lookupGroupsByName for names that don't
+// match any group
+type groupsNotFoundError struct {
+	names []string
+}
+
+func (e *groupsNotFoundError) Error() string {
+	return fmt.Sprintf("groups not found: %s", strings.Join(e.names, ", "))
+}
+
+// groupsAmbiguousError is returned by lookupGroupsByName for names shared by
+// several groups
+type groupsAmbiguousError struct {
+	matches map[string][]string // name -> IDs
+}
+
+func (e *groupsAmbiguousError) Error() string {
+	names := make([]string, 0, len(e.matches))
+	for name := range e.matches {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	lines := make([]string, 0, len(names))
+	for _, name := range names {
+		lines = append(lines, fmt.Sprintf("%q matches groups %s", name, strings.Join(e.matches[name], ", ")))
+	}
+	return fmt.Sprintf("ambiguous group names, reference the groups by ID instead:\n%s", strings.Join(lines, "\n"))
+}
+
+// userGroupLister lists one page of the user groups matching the optionals.
+// On failure, it returns the response of the failed request, if any.
+type userGroupLister func(optionals map[string]interface{}) ([]jcapiv2.UserGroup, *http.Response, error)
+
+// pageUserGroups returns the userGroupLister backed by the v2 API
+func pageUserGroups(client *jcapiv2.APIClient) userGroupLister {
+	return func(optionals map[string]interface{}) ([]jcapiv2.UserGroup, *http.Response, error) {
+		return client.UserGroupsApi.GroupsUserList(
+			context.Background(), "application/json", "application/json", optionals)
+	}
+}
+
+// findGroupIDsByName returns the IDs of all groups named exactly name, case
+// sensitive, paging through all groups the name filter matches. On failure,
+// it returns the response of the failed request, if any.
+func findGroupIDsByName(list userGroupLister, name string) ([]string, *http.Response, error) {
+	var ids []string
+	var res *http.Response
+	err := forEachPage(func(optionals map[string]interface{}) (int, error) {
+		optionals["filter"] = []string{"name:eq:" + name}
+		groups, pageRes, err := list(optionals)
+		if err != nil {
+			res = pageRes
+			return 0, err
+		}
+		// The filter isn't case sensitive
+		for _, group := range groups {
+			if group.Name == name {
+				ids = append(ids, group.Id)
+			}
+		}
+		return len(groups), nil
+	})
+	sort.Strings(ids)
+	return ids, res, err
 }
 
 // lookupGroupsByName looks up multiple groups by name concurrently and returns a map of name -> ID
 func lookupGroupsByName(client *jcapiv2.APIClient, groupNames []string) (map[string]string, error) {
+	return lookupGroups(pageUserGroups(client), groupNames)
+}
+
+// lookupGroups implements lookupGroupsByName on top of a userGroupLister
+func lookupGroups(list userGroupLister, groupNames []string) (map[string]string, error) {
 	result := make(map[string]string)
 
 	if len(groupNames) == 0 {
@@ -134,7 +245,7 @@ func lookupGroupsByName(client *jcapiv2.APIClient, groupNames []string) (map[str
 	var wg sync.WaitGroup
 	for i := 0; i < numWorkers; i++ {
 		wg.Add(1)
-		go groupLookupWorker(client, nameChan, resultChan, &wg)
+		go groupLookupWorker(list, nameChan, resultChan, &wg)
 	}
 
 	// Send group names to workers
@@ -150,13 +261,17 @@ func lookupGroupsByName(client *jcapiv2.APIClient, groupNames []string) (map[str
 	// Collect results
 	var notFound []string
 	var errors []string
+	ambiguous := make(map[string][]string)
 	for res := range resultChan {
-		if res.err != nil {
+		switch {
+		case res.err != nil:
 			errors = append(errors, fmt.Sprintf("%s: %s", res.name, res.err.Error()))
-		} else if res.id == "" {
+		case len(res.ids) == 0:
 			notFound = append(notFound, res.name)
-		} else {
-			result[res.name] = res.id
+		case len(res.ids) > 1:
+			ambiguous[res.name] = res.ids
+		default:
+			result[res.name] = res.ids[0]
 		}
 	}
 
@@ -164,23 +279,29 @@ func lookupGroupsByName(client *jcapiv2.APIClient, groupNames []string) (map[str
 		return nil, fmt.Errorf("errors looking up groups:\n%s", strings.Join(errors, "\n"))
 	}
 
+	if len(ambiguous) > 0 {
+		return nil, &groupsAmbiguousError{matches: ambiguous}
+	}
+
 	if len(notFound) > 0 {
-		return nil, fmt.Errorf("groups not found: %s", strings.Join(notFound, ", "))
+		sort.Strings(notFound)
+		return nil, &groupsNotFoundError{names: notFound}
 	}
 
 	log.Printf("[DEBUG] lookupGroupsByName: Successfully looked up %d groups", len(result))
 	return result, nil
 }
 
-// groupLookupWorker looks up groups by name from the channel with exponential backoff retry
-func groupLookupWorker(client *jcapiv2.APIClient, names <-chan string, results chan<- groupLookupResult, wg *sync.WaitGroup) {
+// groupLookupWorker looks up groups by name from the channel with exponential
+// backoff retry of rate limited and failed requests. Unknown and ambiguous
+// names are no errors and returned right away.
+func groupLookupWorker(list userGroupLister, names <-chan string, results chan<- groupLookupResult, wg *sync.WaitGroup) {
 	defer wg.Done()
 
 	for name := range names {
-		filter := fmt.Sprintf(`{"name":"%s"}`, name)
-
 		var lastErr error
-		var foundID string
+		var foundIDs []string
+		var res *http.Response
 
 		for attempt := 0; attempt < maxRetries; attempt++ {
 			if attempt > 0 {
@@ -189,37 +310,13 @@ func groupLookupWorker(client *jcapiv2.APIClient, names <-chan string, results c
 				time.Sleep(backoff)
 			}
 
-			groups, _, err := client.UserGroupsApi.GroupsUserList(
-				context.Background(),
-				"application/json",
-				"application/json",
-				map[string]interface{}{
-					"filter": filter,
-					"limit":  int32(1),
-				},
-			)
-
-			if err != nil {
-				lastErr = err
-				continue
-			}
-
-			// Find exact match (filter might return partial matches)
-			lastErr = nil
-			for _, group := range groups {
-				if group.Name == name {
-					foundID = group.Id
-					break
-				}
+			foundIDs, res, lastErr = findGroupIDsByName(list, name)
+			if lastErr == nil || !isRetryableResponse(res) {
+				break
 			}
-			break
 		}
 
-		if lastErr != nil {
-			results <- groupLookupResult{name: name, err: lastErr}
-		} else {
-			results <- groupLookupResult{name: name, id: foundID}
-		}
+		results <- groupLookupResult{name: name, ids: foundIDs, err: lastErr}
 		time.Sleep(groupOpRateLimitMs * time.Millisecond)
 	}
 }
@@ -335,70 +432,190 @@ func groupIDLookupWorker(client *jcapiv2.APIClient, ids <-chan string, results c
 	}
 }
 
-func resourceUserGroupMembershipsCreate(d *schema.ResourceData, m interface{}) error {
+const (
+	membershipsModeAuthoritative = "authoritative"
+	membershipsModeManagedOnly   = "managed_only"
+)
+
+// userGroupMembershipsAPI is the part of the JumpCloud API used by the
+// jumpcloud_user_group_memberships resource, so that tests can stub it
+type userGroupMembershipsAPI interface {
+	lookupUserID(email string) (string, error)
+	lookupGroupsByName(names []string) (map[string]string, error)
+	groupIDToName(ids []string) (map[string]string, error)
+	userGroupIDs(userID string) ([]string, error)
+	syncUserGroups(userID string, oldGroupIDs, newGroupIDs []string, groupNameToID map[string]string) error
+	userSystemGroupIDs(userID string) ([]string, error)
+	syncUserSystemGroups(userID string, oldGroupIDs, newGroupIDs []string) error
+}
+
+// userGroupMembershipsClient implements userGroupMembershipsAPI with the
+// JumpCloud SDK
+type userGroupMembershipsClient struct {
+	clientv1 *jcapiv1.APIClient
+	clientv2 *jcapiv2.APIClient
+}
+
+func newUserGroupMembershipsClient(m interface{}) userGroupMembershipsAPI {
 	config := m.(*jcapiv2.Configuration)
-	configv1 := convertV2toV1Config(config)
-	clientv1 := jcapiv1.NewAPIClient(configv1)
-	clientv2 := jcapiv2.NewAPIClient(config)
+	return &userGroupMembershipsClient{
+		clientv1: jcapiv1.NewAPIClient(convertV2toV1Config(config)),
+		clientv2: jcapiv2.NewAPIClient(config),
+	}
+}
+
+func (c *userGroupMembershipsClient) lookupUserID(email string) (string, error) {
+	user, err := getUserDetails(c.clientv1, email)
+	if err != nil {
+		return "", err
+	}
+	return user.Id, nil
+}
+
+func (c *userGroupMembershipsClient) lookupGroupsByName(names []string) (map[string]string, error) {
+	return resolveUserGroupNames(c.clientv2, names)
+}
+
+func (c *userGroupMembershipsClient) groupIDToName(ids []string) (map[string]string, error) {
+	return getGroupIDToNameMap(c.clientv2, ids)
+}
 
+func (c *userGroupMembershipsClient) userGroupIDs(userID string) ([]string, error) {
+	return getUserGroupIDs(c.clientv2, userID)
+}
+
+func (c *userGroupMembershipsClient) syncUserGroups(userID string, oldGroupIDs, newGroupIDs []string, groupNameToID map[string]string) error {
+	return syncUserGroups(c.clientv2, userID, oldGroupIDs, newGroupIDs, groupNameToID)
+}
+
+func (c *userGroupMembershipsClient) userSystemGroupIDs(userID string) ([]string, error) {
+	associations, _, err := listUserAssociations(sdkUserAssociations(c.clientv2, userID, "system_group"))
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, 0, len(associations))
+	for _, association := range associations {
+		if association.To != nil {
+			ids = append(ids, association.To.Id)
+		}
+	}
+	return ids, nil
+}
+
+func (c *userGroupMembershipsClient) syncUserSystemGroups(userID string, oldGroupIDs, newGroupIDs []string) error {
+	return syncUserSystemGroups(c.clientv2, userID, oldGroupIDs, newGroupIDs)
+}
+
+// managedGroupIDs returns the IDs of the groups recorded in the state
+func managedGroupIDs(d *schema.ResourceData) []string {
+	return groupIDsOf(d.Get("group_id_set"), d.Get("group_ids"))
+}
+
+// groupIDsOf returns the IDs in a group_id_set value, together with those in
+// a group_ids value, which states written before group_id_set only have
+func groupIDsOf(idSet, idsByName interface{}) []string {
+	set := idSet.(*schema.Set)
+	for _, id := range idsByName.(map[string]interface{}) {
+		set.Add(id)
+	}
+	return expandStringSet(set)
+}
+
+func isManagedOnly(d *schema.ResourceData) bool {
+	return d.Get("mode").(string) == membershipsModeManagedOnly
+}
+
+// desiredGroupIDs returns the IDs of the configured groups, looking up the
+// names unless group_id_set is used
+func desiredGroupIDs(d *schema.ResourceData, api userGroupMembershipsAPI) ([]string, map[string]string, error) {
+	names := expandStringSet(d.Get("groups").(*schema.Set))
+	if len(names) == 0 {
+		return expandStringSet(d.Get("group_id_set").(*schema.Set)), nil, nil
+	}
+
+	groupNameToID, err := api.lookupGroupsByName(names)
+	if err != nil {
+		return nil, nil, err
+	}
+	ids := make([]string, 0, len(groupNameToID))
+	for _, id := range groupNameToID {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids, groupNameToID, nil
+}
+
+func resourceUserGroupMembershipsCreate(d *schema.ResourceData, m interface{}) error {
+	return userGroupMembershipsCreate(d, newUserGroupMembershipsClient(m))
+}
+
+func userGroupMembershipsCreate(d *schema.ResourceData, api userGroupMembershipsAPI) error {
 	userEmail := d.Get("user_email").(string)
 
 	// Look up user by email
-	user, err := getUserDetails(clientv1, userEmail)
+	userID, err := api.lookupUserID(userEmail)
 	if err != nil {
 		return fmt.Errorf("error looking up user by email %s: %s", userEmail, err)
 	}
 
-	userID := user.Id
 	d.SetId(userID)
 	_ = d.Set("user_id", userID)
 
-	// Get desired group names and look them up
-	groupNamesSet := d.Get("groups").(*schema.Set)
-	groupNames := make([]string, 0, groupNamesSet.Len())
-	for _, name := range groupNamesSet.List() {
-		groupNames = append(groupNames, name.(string))
-	}
-
-	groupNameToID, err := lookupGroupsByName(clientv2, groupNames)
+	desired, groupNameToID, err := desiredGroupIDs(d, api)
 	if err != nil {
 		return err
 	}
 
-	// Store the group ID mapping
-	_ = d.Set("group_ids", groupNameToID)
-
 	// Get current group IDs (should be empty for new user, but check anyway)
-	currentGroupIDs, err := getUserGroupIDs(clientv2, userID)
+	currentGroupIDs, err := api.userGroupIDs(userID)
 	if err != nil {
 		return fmt.Errorf("error getting current group memberships: %s", err)
 	}
 
-	// Build list of desired group IDs
-	desiredGroupIDs := make([]string, 0, len(groupNameToID))
-	for _, id := range groupNameToID {
-		desiredGroupIDs = append(desiredGroupIDs, id)
+	// In managed_only mode, groups the user already belongs to are left alone
+	if isManagedOnly(d) {
+		currentGroupIDs = intersectIDs(currentGroupIDs, desired)
 	}
 
 	// Sync memberships
-	if err := syncUserGroupsConcurrent(clientv2, userID, currentGroupIDs, desiredGroupIDs, groupNameToID); err != nil {
+	if err := api.syncUserGroups(userID, currentGroupIDs, desired, groupNameToID); err != nil {
 		return err
 	}
+	_ = d.Set("group_id_set", desired)
+
+	if err := createUserSystemGroups(d, api); err != nil {
+		return err
+	}
+
+	return userGroupMembershipsRead(d, api)
+}
 
-	return resourceUserGroupMembershipsRead(d, m)
+// createUserSystemGroups associates the user with the configured system
+// groups it isn't associated with yet
+func createUserSystemGroups(d *schema.ResourceData, api userGroupMembershipsAPI) error {
+	desired := expandStringSet(d.Get("system_groups").(*schema.Set))
+	if len(desired) == 0 {
+		return nil
+	}
+	current, err := api.userSystemGroupIDs(d.Id())
+	if err != nil {
+		return fmt.Errorf("error getting current system group associations: %s", err)
+	}
+	return api.syncUserSystemGroups(d.Id(), intersectIDs(current, desired), desired)
 }
 
 func resourceUserGroupMembershipsRead(d *schema.ResourceData, m interface{}) error {
-	config := m.(*jcapiv2.Configuration)
-	clientv2 := jcapiv2.NewAPIClient(config)
+	return userGroupMembershipsRead(d, newUserGroupMembershipsClient(m))
+}
 
+func userGroupMembershipsRead(d *schema.ResourceData, api userGroupMembershipsAPI) error {
 	userID := d.Id()
 	if userID == "" {
 		return nil
 	}
 
 	// Get current group IDs for the user
-	currentGroupIDs, err := getUserGroupIDs(clientv2, userID)
+	currentGroupIDs, err := api.userGroupIDs(userID)
 	if err != nil {
 		// If user not found, remove from state
 		if strings.Contains(err.Error(), "not found") || strings.Contains(err.Error(), "404") {
@@ -408,111 +625,94 @@ func resourceUserGroupMembershipsRead(d *schema.ResourceData, m interface{}) err
 		return fmt.Errorf("error getting current group memberships: %s", err)
 	}
 
+	// In managed_only mode, groups outside of the configured set are ignored
+	if isManagedOnly(d) {
+		currentGroupIDs = intersectIDs(currentGroupIDs, managedGroupIDs(d))
+	}
+
 	// Look up group names from IDs
-	groupIDToName, err := getGroupIDToNameMap(clientv2, currentGroupIDs)
+	groupIDToName, err := api.groupIDToName(currentGroupIDs)
 	if err != nil {
 		return fmt.Errorf("error looking up group names: %s", err)
 	}
 
 	// Build the groups list and group_ids map
 	groupNames := make([]string, 0, len(groupIDToName))
-	groupIDs := make(map[string]string)
+	groupIDs := make([]string, 0, len(groupIDToName))
+	groupIDsByName := make(map[string]string)
 	for id, name := range groupIDToName {
 		groupNames = append(groupNames, name)
-		groupIDs[name] = id
+		groupIDs = append(groupIDs, id)
+		groupIDsByName[name] = id
 	}
 
 	// Sort for consistent ordering
 	sort.Strings(groupNames)
+	sort.Strings(groupIDs)
 
 	_ = d.Set("groups", groupNames)
-	_ = d.Set("group_ids", groupIDs)
+	_ = d.Set("group_id_set", groupIDs)
+	_ = d.Set("group_ids", groupIDsByName)
+	if d.Get("mode").(string) == "" {
+		_ = d.Set("mode", membershipsModeAuthoritative)
+	}
+
+	if managed := expandStringSet(d.Get("system_groups").(*schema.Set)); len(managed) > 0 {
+		current, err := api.userSystemGroupIDs(userID)
+		if err != nil {
+			return fmt.Errorf("error getting current system group associations: %s", err)
+		}
+		_ = d.Set("system_groups", intersectIDs(current, managed))
+	}
 
 	return nil
 }
 
 func resourceUserGroupMembershipsUpdate(d *schema.ResourceData, m interface{}) error {
-	config := m.(*jcapiv2.Configuration)
-	clientv2 := jcapiv2.NewAPIClient(config)
+	return userGroupMembershipsUpdate(d, newUserGroupMembershipsClient(m))
+}
 
+func userGroupMembershipsUpdate(d *schema.ResourceData, api userGroupMembershipsAPI) error {
 	userID := d.Id()
 
-	if d.HasChange("groups") {
-		// Get old and new group names
-		oldGroupsRaw, newGroupsRaw := d.GetChange("groups")
-		oldGroupsSet := oldGroupsRaw.(*schema.Set)
-		newGroupsSet := newGroupsRaw.(*schema.Set)
+	if d.HasChanges("groups", "group_id_set") {
+		// The state holds the IDs of the groups as of the last refresh
+		oldIDSet, _ := d.GetChange("group_id_set")
+		oldIDsByName, _ := d.GetChange("group_ids")
+		oldGroupIDs := groupIDsOf(oldIDSet, oldIDsByName)
 
-		oldGroupNames := make([]string, 0, oldGroupsSet.Len())
-		for _, name := range oldGroupsSet.List() {
-			oldGroupNames = append(oldGroupNames, name.(string))
-		}
-
-		newGroupNames := make([]string, 0, newGroupsSet.Len())
-		for _, name := range newGroupsSet.List() {
-			newGroupNames = append(newGroupNames, name.(string))
-		}
-
-		// Look up all group names (old and new combined)
-		allGroupNames := make(map[string]bool)
-		for _, name := range oldGroupNames {
-			allGroupNames[name] = true
-		}
-		for _, name := range newGroupNames {
-			allGroupNames[name] = true
-		}
-
-		allGroupNamesList := make([]string, 0, len(allGroupNames))
-		for name := range allGroupNames {
-			allGroupNamesList = append(allGroupNamesList, name)
-		}
-
-		groupNameToID, err := lookupGroupsByName(clientv2, allGroupNamesList)
+		desired, groupNameToID, err := desiredGroupIDs(d, api)
 		if err != nil {
 			return err
 		}
 
-		// Convert names to IDs
-		oldGroupIDs := make([]string, 0, len(oldGroupNames))
-		for _, name := range oldGroupNames {
-			if id, ok := groupNameToID[name]; ok {
-				oldGroupIDs = append(oldGroupIDs, id)
-			}
-		}
-
-		newGroupIDs := make([]string, 0, len(newGroupNames))
-		for _, name := range newGroupNames {
-			if id, ok := groupNameToID[name]; ok {
-				newGroupIDs = append(newGroupIDs, id)
-			}
-		}
-
 		// Sync memberships concurrently
-		if err := syncUserGroupsConcurrent(clientv2, userID, oldGroupIDs, newGroupIDs, groupNameToID); err != nil {
+		if err := api.syncUserGroups(userID, oldGroupIDs, desired, groupNameToID); err != nil {
 			return err
 		}
+		_ = d.Set("group_id_set", desired)
+	}
 
-		// Update group_ids map with only the new groups
-		newGroupIDsMap := make(map[string]string)
-		for _, name := range newGroupNames {
-			if id, ok := groupNameToID[name]; ok {
-				newGroupIDsMap[name] = id
-			}
+	if d.HasChange("system_groups") {
+		oldRaw, newRaw := d.GetChange("system_groups")
+		if err := api.syncUserSystemGroups(userID,
+			expandStringSet(oldRaw.(*schema.Set)), expandStringSet(newRaw.(*schema.Set))); err != nil {
+			return err
 		}
-		_ = d.Set("group_ids", newGroupIDsMap)
 	}
 
-	return resourceUserGroupMembershipsRead(d, m)
+	return userGroupMembershipsRead(d, api)
 }
 
 func resourceUserGroupMembershipsDelete(d *schema.ResourceData, m interface{}) error {
-	config := m.(*jcapiv2.Configuration)
-	clientv2 := jcapiv2.NewAPIClient(config)
+	return userGroupMembershipsDelete(d, newUserGroupMembershipsClient(m))
+}
 
+func userGroupMembershipsDelete(d *schema.ResourceData, api userGroupMembershipsAPI) error {
 	userID := d.Id()
 
 	// Get current group IDs
-	currentGroupIDs, err := getUserGroupIDs(clientv2, userID)
+	currentGroupIDs, err := api.userGroupIDs(userID)
 	if err != nil {
 		// If user not found, consider delete successful
 		if strings.Contains(err.Error(), "not found") || strings.Contains(err.Error(), "404") {
@@ -521,179 +721,23 @@ func resourceUserGroupMembershipsDelete(d *schema.ResourceData, m interface{}) e
 		return fmt.Errorf("error getting current group memberships: %s", err)
 	}
 
-	// Remove user from all groups (sync to empty list)
-	if err := syncUserGroupsConcurrent(clientv2, userID, currentGroupIDs, []string{}, nil); err != nil {
-		return err
+	// In managed_only mode, only the groups recorded in the state are left
+	if isManagedOnly(d) {
+		currentGroupIDs = intersectIDs(currentGroupIDs, managedGroupIDs(d))
 	}
 
-	d.SetId("")
-	return nil
-}
-
-// syncUserGroupsConcurrent synchronizes a user's group memberships using concurrent API calls
-func syncUserGroupsConcurrent(client *jcapiv2.APIClient, userID string, oldGroupIDs, newGroupIDs []string, groupNameToID map[string]string) error {
-	// Build reverse lookup for logging
-	groupIDToName := make(map[string]string)
-	for name, id := range groupNameToID {
-		groupIDToName[id] = name
-	}
-
-	// Convert slices to maps for efficient lookup
-	oldGroups := make(map[string]bool)
-	for _, id := range oldGroupIDs {
-		if id != "" {
-			oldGroups[id] = true
-		}
-	}
-
-	newGroups := make(map[string]bool)
-	for _, id := range newGroupIDs {
-		if id != "" {
-			newGroups[id] = true
-		}
-	}
-
-	// Build list of operations
-	var operations []groupOperation
-
-	// Groups to add (in newGroups but not in oldGroups)
-	for groupID := range newGroups {
-		if !oldGroups[groupID] {
-			operations = append(operations, groupOperation{
-				groupID:   groupID,
-				groupName: groupIDToName[groupID],
-				op:        "add",
-			})
-		}
+	// Remove user from the groups (sync to empty list)
+	if err := api.syncUserGroups(userID, currentGroupIDs, []string{}, nil); err != nil {
+		return err
 	}
 
-	// Groups to remove (in oldGroups but not in newGroups)
-	for groupID := range oldGroups {
-		if !newGroups[groupID] {
-			operations = append(operations, groupOperation{
-				groupID:   groupID,
-				groupName: groupIDToName[groupID],
-				op:        "remove",
-			})
+	// System groups are always managed_only
+	if managed := expandStringSet(d.Get("system_groups").(*schema.Set)); len(managed) > 0 {
+		if err := api.syncUserSystemGroups(userID, managed, []string{}); err != nil {
+			return err
 		}
 	}
 
-	if len(operations) == 0 {
-		log.Println("[DEBUG] syncUserGroupsConcurrent: No changes needed")
-		return nil
-	}
-
-	log.Printf("[DEBUG] syncUserGroupsConcurrent: Processing %d group operations concurrently (max %d workers)", len(operations), maxConcurrentGroupOps)
-
-	// Execute operations concurrently
-	errors := executeGroupOperationsConcurrently(client, userID, operations)
-
-	if len(errors) > 0 {
-		return fmt.Errorf("group synchronization partially failed:\n%s", strings.Join(errors, "\n"))
-	}
-
+	d.SetId("")
 	return nil
 }
-
-// executeGroupOperationsConcurrently processes group membership operations using a worker pool
-func executeGroupOperationsConcurrently(client *jcapiv2.APIClient, userID string, operations []groupOperation) []string {
-	numWorkers := maxConcurrentGroupOps
-	if len(operations) < numWorkers {
-		numWorkers = len(operations)
-	}
-
-	// Channels for work distribution and results
-	opsChan := make(chan groupOperation, len(operations))
-	errChan := make(chan string, len(operations))
-
-	// WaitGroup to track worker completion
-	var wg sync.WaitGroup
-
-	// Start workers
-	for i := 0; i < numWorkers; i++ {
-		wg.Add(1)
-		go groupOperationWorker(client, userID, opsChan, errChan, &wg)
-	}
-
-	// Send operations to workers
-	for _, op := range operations {
-		opsChan <- op
-	}
-	close(opsChan)
-
-	// Wait for all workers to complete
-	wg.Wait()
-	close(errChan)
-
-	// Collect errors
-	var errors []string
-	for errMsg := range errChan {
-		errors = append(errors, errMsg)
-	}
-
-	addCount := 0
-	removeCount := 0
-	for _, op := range operations {
-		if op.op == "add" {
-			addCount++
-		} else {
-			removeCount++
-		}
-	}
-	log.Printf("[DEBUG] syncUserGroupsConcurrent: Processed %d add operations, %d remove operations, %d errors",
-		addCount, removeCount, len(errors))
-
-	return errors
-}
-
-// groupOperationWorker processes group operations from the channel with exponential backoff retry
-func groupOperationWorker(client *jcapiv2.APIClient, userID string, ops <-chan groupOperation, errors chan<- string, wg *sync.WaitGroup) {
-	defer wg.Done()
-
-	for op := range ops {
-		opName := "Adding"
-		if op.op == "remove" {
-			opName = "Removing"
-		}
-		log.Printf("[DEBUG] %s user %s to/from group %s (%s)", opName, userID, op.groupName, op.groupID)
-
-		payload := jcapiv2.UserGroupMembersReq{
-			Op:    op.op,
-			Type_: "user",
-			Id:    userID,
-		}
-		req := map[string]interface{}{
-			"body": payload,
-		}
-
-		var lastErr error
-		for attempt := 0; attempt < maxRetries; attempt++ {
-			if attempt > 0 {
-				backoff := time.Duration(baseBackoffMs*(1<<attempt)) * time.Millisecond
-				log.Printf("[DEBUG] groupOperationWorker: Retry %d for %s user %s to/from group %s after %v",
-					attempt, op.op, userID, op.groupName, backoff)
-				time.Sleep(backoff)
-			}
-
-			res, err := client.UserGroupMembersMembershipApi.GraphUserGroupMembersPost(
-				context.TODO(), op.groupID, "", "", req)
-
-			if err != nil {
-				lastErr = fmt.Errorf("error %s user %s to/from group %s (%s): %s; response = %+v",
-					op.op, userID, op.groupName, op.groupID, err, res)
-				continue
-			}
-
-			lastErr = nil
-			break
-		}
-
-		if lastErr != nil {
-			log.Printf("[ERROR] %s", lastErr.Error())
-			errors <- lastErr.Error()
-		}
-
-		// Rate limiting between operations
-		time.Sleep(groupOpRateLimitMs * time.Millisecond)
-	}
-}
@@ -1,8 +1,17 @@
 package jumpcloud
 
 import (
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
 	"sync"
 	"testing"
+
+	jcapiv2 "github.com/TheJumpCloud/jcapi-go/v2"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 // TestGroupOperationStructure tests that groupOperation struct is properly defined
@@ -52,10 +61,10 @@ func TestExponentialBackoffCalculation(t *testing.T) {
 		attempt         int
 		expectedBackoff int // in milliseconds
 	}{
-		{0, 0},                      // No backoff on first attempt
-		{1, baseBackoffMs * 2},      // 200ms
-		{2, baseBackoffMs * 4},      // 400ms
-		{3, baseBackoffMs * 8},      // 800ms
+		{0, 0},                 // No backoff on first attempt
+		{1, baseBackoffMs * 2}, // 200ms
+		{2, baseBackoffMs * 4}, // 400ms
+		{3, baseBackoffMs * 8}, // 800ms
 	}
 
 	for _, tc := range testCases {
@@ -102,52 +111,52 @@ func TestWorkerPoolConcurrency(t *testing.T) {
 // TestBuildGroupOperations tests building operation lists from old/new group sets
 func TestBuildGroupOperations(t *testing.T) {
 	testCases := []struct {
-		name          string
-		oldGroupIDs   []string
-		newGroupIDs   []string
-		expectedAdds  int
+		name            string
+		oldGroupIDs     []string
+		newGroupIDs     []string
+		expectedAdds    int
 		expectedRemoves int
 	}{
 		{
-			name:          "no changes",
-			oldGroupIDs:   []string{"a", "b"},
-			newGroupIDs:   []string{"a", "b"},
-			expectedAdds:  0,
+			name:            "no changes",
+			oldGroupIDs:     []string{"a", "b"},
+			newGroupIDs:     []string{"a", "b"},
+			expectedAdds:    0,
 			expectedRemoves: 0,
 		},
 		{
-			name:          "add only",
-			oldGroupIDs:   []string{"a"},
-			newGroupIDs:   []string{"a", "b", "c"},
-			expectedAdds:  2,
+			name:            "add only",
+			oldGroupIDs:     []string{"a"},
+			newGroupIDs:     []string{"a", "b", "c"},
+			expectedAdds:    2,
 			expectedRemoves: 0,
 		},
 		{
-			name:          "remove only",
-			oldGroupIDs:   []string{"a", "b", "c"},
-			newGroupIDs:   []string{"a"},
-			expectedAdds:  0,
+			name:            "remove only",
+			oldGroupIDs:     []string{"a", "b", "c"},
+			newGroupIDs:     []string{"a"},
+			expectedAdds:    0,
 			expectedRemoves: 2,
 		},
 		{
-			name:          "add and remove",
-			oldGroupIDs:   []string{"a", "b"},
-			newGroupIDs:   []string{"b", "c"},
-			expectedAdds:  1,
+			name:            "add and remove",
+			oldGroupIDs:     []string{"a", "b"},
+			newGroupIDs:     []string{"b", "c"},
+			expectedAdds:    1,
 			expectedRemoves: 1,
 		},
 		{
-			name:          "empty to some",
-			oldGroupIDs:   []string{},
-			newGroupIDs:   []string{"a", "b"},
-			expectedAdds:  2,
+			name:            "empty to some",
+			oldGroupIDs:     []string{},
+			newGroupIDs:     []string{"a", "b"},
+			expectedAdds:    2,
 			expectedRemoves: 0,
 		},
 		{
-			name:          "some to empty",
-			oldGroupIDs:   []string{"a", "b"},
-			newGroupIDs:   []string{},
-			expectedAdds:  0,
+			name:            "some to empty",
+			oldGroupIDs:     []string{"a", "b"},
+			newGroupIDs:     []string{},
+			expectedAdds:    0,
 			expectedRemoves: 2,
 		},
 	}
@@ -271,3 +280,372 @@ func TestEmptyOperations(t *testing.T) {
 
 	t.Error("Should have detected empty operations")
 }
+
+// stubMembershipsAPI is an in-memory userGroupMembershipsAPI for a single user
+type stubMembershipsAPI struct {
+	userID  string
+	groups  map[string]string // name -> ID
+	member  map[string]bool   // group IDs the user belongs to
+	added   []string
+	removed []string
+	admin   map[string]bool // system group IDs the user administers
+}
+
+func newStubMembershipsAPI(memberOf ...string) *stubMembershipsAPI {
+	api := &stubMembershipsAPI{
+		userID: "user1",
+		groups: map[string]string{"dev": "g-dev", "ops": "g-ops", "hr": "g-hr", "sales": "g-sales"},
+		member: map[string]bool{},
+		admin:  map[string]bool{},
+	}
+	for _, name := range memberOf {
+		api.member[api.groups[name]] = true
+	}
+	return api
+}
+
+func (s *stubMembershipsAPI) lookupUserID(email string) (string, error) {
+	return s.userID, nil
+}
+
+func (s *stubMembershipsAPI) lookupGroupsByName(names []string) (map[string]string, error) {
+	result := make(map[string]string)
+	for _, name := range names {
+		id, ok := s.groups[name]
+		if !ok {
+			return nil, &groupsNotFoundError{names: []string{name}}
+		}
+		result[name] = id
+	}
+	return result, nil
+}
+
+func (s *stubMembershipsAPI) groupIDToName(ids []string) (map[string]string, error) {
+	result := make(map[string]string)
+	for name, id := range s.groups {
+		for _, wanted := range ids {
+			if id == wanted {
+				result[id] = name
+			}
+		}
+	}
+	return result, nil
+}
+
+func (s *stubMembershipsAPI) userGroupIDs(userID string) ([]string, error) {
+	if userID != s.userID {
+		return nil, errors.New("404 Not Found")
+	}
+	ids := []string{}
+	for id := range s.member {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
+
+func (s *stubMembershipsAPI) syncUserGroups(userID string, oldGroupIDs, newGroupIDs []string, groupNameToID map[string]string) error {
+	added, removed := diffIDs(oldGroupIDs, newGroupIDs)
+	for _, id := range added {
+		s.member[id] = true
+	}
+	for _, id := range removed {
+		delete(s.member, id)
+	}
+	s.added = append(s.added, added...)
+	s.removed = append(s.removed, removed...)
+	return nil
+}
+
+func (s *stubMembershipsAPI) userSystemGroupIDs(userID string) ([]string, error) {
+	ids := []string{}
+	for id := range s.admin {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
+
+func (s *stubMembershipsAPI) syncUserSystemGroups(userID string, oldGroupIDs, newGroupIDs []string) error {
+	added, removed := diffIDs(oldGroupIDs, newGroupIDs)
+	for _, id := range added {
+		s.admin[id] = true
+	}
+	for _, id := range removed {
+		delete(s.admin, id)
+	}
+	return nil
+}
+
+func membershipsResourceData(t *testing.T, mode string, groups ...string) *schema.ResourceData {
+	raw := map[string]interface{}{
+		"user_email": "user1@testorg.com",
+		"groups":     toInterfaceSlice(groups),
+	}
+	if mode != "" {
+		raw["mode"] = mode
+	}
+	return schema.TestResourceDataRaw(t, resourceUserGroupMemberships().Schema, raw)
+}
+
+func toInterfaceSlice(values []string) []interface{} {
+	out := make([]interface{}, 0, len(values))
+	for _, v := range values {
+		out = append(out, v)
+	}
+	return out
+}
+
+func TestUserGroupMembershipsAuthoritative(t *testing.T) {
+	api := newStubMembershipsAPI("hr")
+	d := membershipsResourceData(t, "", "dev", "ops")
+
+	if err := userGroupMembershipsCreate(d, api); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(api.removed, []string{"g-hr"}) {
+		t.Errorf("Expected the unlisted group to be removed, got %v", api.removed)
+	}
+
+	api.member["g-sales"] = true
+	if err := userGroupMembershipsRead(d, api); err != nil {
+		t.Fatal(err)
+	}
+	groups := expandStringSet(d.Get("groups").(*schema.Set))
+	sort.Strings(groups)
+	if !reflect.DeepEqual(groups, []string{"dev", "ops", "sales"}) {
+		t.Errorf("Expected all groups to be read, got %v", groups)
+	}
+
+	api.removed = nil
+	if err := userGroupMembershipsDelete(d, api); err != nil {
+		t.Fatal(err)
+	}
+	if len(api.member) != 0 {
+		t.Errorf("Expected the user to be removed from all groups, still in %v", api.member)
+	}
+}
+
+func TestUserGroupMembershipsManagedOnly(t *testing.T) {
+	api := newStubMembershipsAPI("hr")
+	d := membershipsResourceData(t, membershipsModeManagedOnly, "dev", "ops")
+
+	if err := userGroupMembershipsCreate(d, api); err != nil {
+		t.Fatal(err)
+	}
+	if len(api.removed) != 0 {
+		t.Errorf("Expected no group to be removed, got %v", api.removed)
+	}
+	if !api.member["g-hr"] || !api.member["g-dev"] || !api.member["g-ops"] {
+		t.Errorf("Expected the user to be added to the listed groups only, got %v", api.member)
+	}
+
+	// Groups granted by others are ignored
+	api.member["g-sales"] = true
+	if err := userGroupMembershipsRead(d, api); err != nil {
+		t.Fatal(err)
+	}
+	groups := expandStringSet(d.Get("groups").(*schema.Set))
+	sort.Strings(groups)
+	if !reflect.DeepEqual(groups, []string{"dev", "ops"}) {
+		t.Errorf("Expected only the managed groups to be read, got %v", groups)
+	}
+
+	// A managed group removed elsewhere shows up as missing
+	delete(api.member, "g-ops")
+	if err := userGroupMembershipsRead(d, api); err != nil {
+		t.Fatal(err)
+	}
+	if groups := expandStringSet(d.Get("groups").(*schema.Set)); !reflect.DeepEqual(groups, []string{"dev"}) {
+		t.Errorf("Expected the group removed elsewhere to be missing, got %v", groups)
+	}
+
+	if err := userGroupMembershipsDelete(d, api); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(api.removed, []string{"g-dev"}) {
+		t.Errorf("Expected only the managed groups to be removed, got %v", api.removed)
+	}
+	if !api.member["g-hr"] || !api.member["g-sales"] {
+		t.Errorf("Expected the other memberships to be kept, got %v", api.member)
+	}
+}
+
+func TestUserGroupMembershipsDeleteMissingUser(t *testing.T) {
+	api := newStubMembershipsAPI()
+	d := membershipsResourceData(t, membershipsModeManagedOnly, "dev")
+	d.SetId("deleted")
+
+	if err := userGroupMembershipsDelete(d, api); err != nil {
+		t.Errorf("Expected deleting the memberships of a deleted user to succeed, got %s", err)
+	}
+}
+
+func TestUserGroupMembershipsGroupIDs(t *testing.T) {
+	api := newStubMembershipsAPI("hr")
+	d := schema.TestResourceDataRaw(t, resourceUserGroupMemberships().Schema, map[string]interface{}{
+		"user_email":   "user1@testorg.com",
+		"group_id_set": []interface{}{"g-dev", "g-ops"},
+	})
+
+	if err := userGroupMembershipsCreate(d, api); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(api.removed, []string{"g-hr"}) {
+		t.Errorf("Expected the unlisted group to be removed, got %v", api.removed)
+	}
+	groups := expandStringSet(d.Get("groups").(*schema.Set))
+	sort.Strings(groups)
+	if !reflect.DeepEqual(groups, []string{"dev", "ops"}) {
+		t.Errorf("Expected the names of the groups to be read, got %v", groups)
+	}
+	if byName := d.Get("group_ids").(map[string]interface{}); byName["dev"] != "g-dev" {
+		t.Errorf("Expected the group IDs by name, got %v", byName)
+	}
+}
+
+func TestUserGroupMembershipsSystemGroups(t *testing.T) {
+	api := newStubMembershipsAPI()
+	api.admin["sg-other"] = true
+	d := schema.TestResourceDataRaw(t, resourceUserGroupMemberships().Schema, map[string]interface{}{
+		"user_email":    "user1@testorg.com",
+		"groups":        []interface{}{"dev"},
+		"system_groups": []interface{}{"sg-servers"},
+	})
+
+	if err := userGroupMembershipsCreate(d, api); err != nil {
+		t.Fatal(err)
+	}
+	if !api.admin["sg-servers"] || !api.admin["sg-other"] {
+		t.Errorf("Expected the system group to be added and the others kept, got %v", api.admin)
+	}
+	if ids := expandStringSet(d.Get("system_groups").(*schema.Set)); !reflect.DeepEqual(ids, []string{"sg-servers"}) {
+		t.Errorf("Expected only the managed system groups to be read, got %v", ids)
+	}
+
+	if err := userGroupMembershipsDelete(d, api); err != nil {
+		t.Fatal(err)
+	}
+	if api.admin["sg-servers"] || !api.admin["sg-other"] {
+		t.Errorf("Expected only the managed system group to be removed, got %v", api.admin)
+	}
+}
+
+func TestUserGroupMembershipsManagedGroupIDsFromMap(t *testing.T) {
+	// States written before group_id_set only record the group_ids map
+	api := newStubMembershipsAPI("dev", "hr")
+	d := membershipsResourceData(t, membershipsModeManagedOnly, "dev")
+	d.SetId("user1")
+	if err := d.Set("group_ids", map[string]interface{}{"dev": "g-dev"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := userGroupMembershipsRead(d, api); err != nil {
+		t.Fatal(err)
+	}
+	if groups := expandStringSet(d.Get("groups").(*schema.Set)); !reflect.DeepEqual(groups, []string{"dev"}) {
+		t.Errorf("Expected the managed groups of the map to be read, got %v", groups)
+	}
+	if ids := expandStringSet(d.Get("group_id_set").(*schema.Set)); !reflect.DeepEqual(ids, []string{"g-dev"}) {
+		t.Errorf("Expected group_id_set to be filled, got %v", ids)
+	}
+}
+
+// stubGroupLister pages through groups like the API, with a filter ignoring case
+func stubGroupLister(groups []jcapiv2.UserGroup) userGroupLister {
+	return func(optionals map[string]interface{}) ([]jcapiv2.UserGroup, *http.Response, error) {
+		name := strings.TrimPrefix(optionals["filter"].([]string)[0], "name:eq:")
+		var matches []jcapiv2.UserGroup
+		for _, group := range groups {
+			if strings.EqualFold(group.Name, name) {
+				matches = append(matches, group)
+			}
+		}
+		skip, limit := int(optionals["skip"].(int32)), int(optionals["limit"].(int32))
+		if skip >= len(matches) {
+			return nil, nil, nil
+		}
+		if skip+limit > len(matches) {
+			return matches[skip:], nil, nil
+		}
+		return matches[skip : skip+limit], nil, nil
+	}
+}
+
+func TestLookupGroups(t *testing.T) {
+	// The exact match is on the second page
+	var groups []jcapiv2.UserGroup
+	for i := 0; i < 100; i++ {
+		groups = append(groups, jcapiv2.UserGroup{Id: fmt.Sprintf("g-%d", i), Name: "DEV"})
+	}
+	groups = append(groups,
+		jcapiv2.UserGroup{Id: "g-dev", Name: "dev"},
+		jcapiv2.UserGroup{Id: "g-ops-1", Name: "ops"},
+		jcapiv2.UserGroup{Id: "g-ops-2", Name: "ops"},
+	)
+	list := stubGroupLister(groups)
+
+	result, err := lookupGroups(list, []string{"dev"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(result, map[string]string{"dev": "g-dev"}) {
+		t.Errorf("Expected the exact match, got %v", result)
+	}
+
+	_, err = lookupGroups(list, []string{"dev", "ops"})
+	var ambiguous *groupsAmbiguousError
+	if !errors.As(err, &ambiguous) {
+		t.Fatalf("Expected an ambiguous names error, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "g-ops-1") || !strings.Contains(err.Error(), "g-ops-2") {
+		t.Errorf("Expected the error to list the matching groups, got %s", err)
+	}
+
+	_, err = lookupGroups(list, []string{"Dev"})
+	var notFound *groupsNotFoundError
+	if !errors.As(err, &notFound) {
+		t.Errorf("Expected names to be case-sensitive, got %v", err)
+	}
+}
+
+func TestGroupLookupWorkerRetries(t *testing.T) {
+	testCases := []struct {
+		name     string
+		status   int
+		expected int
+	}{
+		{"bad request", http.StatusBadRequest, 1},
+		{"rate limited", http.StatusTooManyRequests, maxRetries},
+		{"server error", http.StatusServiceUnavailable, maxRetries},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			calls := 0
+			list := func(optionals map[string]interface{}) ([]jcapiv2.UserGroup, *http.Response, error) {
+				calls++
+				return nil, &http.Response{StatusCode: tc.status}, errors.New("failed")
+			}
+			if _, err := lookupGroups(list, []string{"dev"}); err == nil {
+				t.Fatal("Expected the lookup to fail")
+			}
+			if calls != tc.expected {
+				t.Errorf("Expected %d requests, got %d", tc.expected, calls)
+			}
+		})
+	}
+
+	// Unknown names aren't retried
+	calls := 0
+	list := func(optionals map[string]interface{}) ([]jcapiv2.UserGroup, *http.Response, error) {
+		calls++
+		return nil, nil, nil
+	}
+	if _, err := lookupGroups(list, []string{"dev"}); err == nil {
+		t.Fatal("Expected a not found error")
+	}
+	if calls != 1 {
+		t.Errorf("Expected a single request for an unknown name, got %d", calls)
+	}
+}
@@ -0,0 +1,136 @@
+package jumpcloud
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	jcapiv2 "github.com/TheJumpCloud/jcapi-go/v2"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func resourceUserGroupSuggestionApproval() *schema.Resource {
+	return &schema.Resource{
+		Description: "Approves pending member suggestions of a JumpCloud user group with membership_method `dynamic_review`. " +
+			"Approvals are applied once and cannot be undone; destroying the resource only removes it from the state.",
+		Create: resourceUserGroupSuggestionApprovalCreate,
+		Read:   resourceUserGroupSuggestionApprovalRead,
+		Delete: resourceUserGroupSuggestionApprovalDelete,
+		Schema: map[string]*schema.Schema{
+			"group_id": {
+				Description: "The ID of the `jumpcloud_user_group` resource.",
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+			},
+			"user_ids": {
+				Description: "The IDs of the users whose pending suggestions, additions or removals, are approved.",
+				Type:        schema.TypeSet,
+				Required:    true,
+				ForceNew:    true,
+				MinItems:    1,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+		},
+	}
+}
+
+// suggestionApprovalID identifies an approval by its group and users, so that
+// several approvals of the same group can coexist
+func suggestionApprovalID(groupID string, userIDs []string) string {
+	sorted := append([]string{}, userIDs...)
+	sort.Strings(sorted)
+	sum := sha256.Sum256([]byte(strings.Join(sorted, ",")))
+	return fmt.Sprintf("%s/%x", groupID, sum[:8])
+}
+
+// userGroupSuggestionLister lists the pending member suggestions of a group
+type userGroupSuggestionLister func(groupID string) ([]UserGroupSuggestion, error)
+
+// userGroupSuggestionApplier applies the member suggestions of a group
+type userGroupSuggestionApplier func(groupID string, body UserGroupSuggestionApproval) error
+
+// applyUserGroupSuggestions returns the userGroupSuggestionApplier backed by
+// the v2 usergroups API
+func applyUserGroupSuggestions(config *jcapiv2.Configuration) userGroupSuggestionApplier {
+	return func(groupID string, body UserGroupSuggestionApproval) error {
+		return doAPIRequest(config, http.MethodPost,
+			config.BasePath+"/usergroups/"+groupID+"/suggestions", body, nil)
+	}
+}
+
+// approveUserGroupSuggestions applies the pending additions and removals of
+// the given users. It fails without applying anything if one of the users has
+// no pending suggestion.
+func approveUserGroupSuggestions(list userGroupSuggestionLister, apply userGroupSuggestionApplier,
+	groupID string, userIDs []string) error {
+
+	suggestions, err := list(groupID)
+	if err != nil {
+		return fmt.Errorf("error listing suggestions of user group %s: %s", groupID, err)
+	}
+
+	// Only additions and removals can be approved
+	additions, removals := splitUserGroupSuggestions(suggestions)
+	pending := map[string]bool{}
+	for _, id := range append(additions, removals...) {
+		pending[id] = true
+	}
+
+	approved := append([]string{}, userIDs...)
+	sort.Strings(approved)
+	var missing []string
+	for _, id := range approved {
+		if !pending[id] {
+			missing = append(missing, id)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("user group %s has no pending suggestions for users %s", groupID, strings.Join(missing, ", "))
+	}
+
+	if err := apply(groupID, UserGroupSuggestionApproval{UserIDs: approved}); err != nil {
+		return fmt.Errorf("error approving suggestions of user group %s: %s", groupID, err)
+	}
+	return nil
+}
+
+func resourceUserGroupSuggestionApprovalCreate(d *schema.ResourceData, m interface{}) error {
+	config := m.(*jcapiv2.Configuration)
+	groupID := d.Get("group_id").(string)
+	userIDs := expandStringSet(d.Get("user_ids").(*schema.Set))
+
+	list := func(groupID string) ([]UserGroupSuggestion, error) {
+		return listUserGroupSuggestions(config, groupID)
+	}
+	if err := approveUserGroupSuggestions(list, applyUserGroupSuggestions(config), groupID, userIDs); err != nil {
+		return err
+	}
+
+	d.SetId(suggestionApprovalID(groupID, userIDs))
+	return resourceUserGroupSuggestionApprovalRead(d, m)
+}
+
+func resourceUserGroupSuggestionApprovalRead(d *schema.ResourceData, m interface{}) error {
+	config := m.(*jcapiv2.Configuration)
+
+	// The approval itself isn't stored, so only the group can disappear
+	_, ok, err := userGroupReadHelper(config, d.Get("group_id").(string))
+	if err != nil {
+		return err
+	}
+	if !ok {
+		d.SetId("")
+	}
+	return nil
+}
+
+func resourceUserGroupSuggestionApprovalDelete(d *schema.ResourceData, m interface{}) error {
+	// Approved suggestions cannot be revoked
+	d.SetId("")
+	return nil
+}
@@ -0,0 +1,66 @@
+package jumpcloud
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	jcapiv2 "github.com/TheJumpCloud/jcapi-go/v2"
+)
+
+func TestSuggestionApprovalID(t *testing.T) {
+	id := suggestionApprovalID("group", []string{"b", "a"})
+	if id != suggestionApprovalID("group", []string{"a", "b"}) {
+		t.Error("Expected the ID to be independent of the order of the users")
+	}
+	if id == suggestionApprovalID("group", []string{"a"}) {
+		t.Error("Expected different users to produce different IDs")
+	}
+	if len(id) != len("group/")+16 {
+		t.Errorf("Unexpected ID %s", id)
+	}
+}
+
+func TestApproveUserGroupSuggestions(t *testing.T) {
+	list := func(groupID string) ([]UserGroupSuggestion, error) {
+		if groupID != "group" {
+			t.Errorf("Unexpected group %s", groupID)
+		}
+		return []UserGroupSuggestion{
+			{Object: &jcapiv2.GraphObject{Id: "a"}, Op: "add"},
+			{Object: &jcapiv2.GraphObject{Id: "b"}, Op: "remove"},
+			{Object: &jcapiv2.GraphObject{Id: "c"}, Op: "replace"},
+		}, nil
+	}
+
+	var body []byte
+	apply := func(groupID string, approval UserGroupSuggestionApproval) error {
+		var err error
+		body, err = json.Marshal(approval)
+		return err
+	}
+	if err := approveUserGroupSuggestions(list, apply, "group", []string{"b", "a"}); err != nil {
+		t.Fatal(err)
+	}
+	if string(body) != `{"user_ids":["a","b"]}` {
+		t.Errorf("Unexpected request body %s", body)
+	}
+
+	// Users without a pending addition or removal fail before anything is applied
+	body = nil
+	err := approveUserGroupSuggestions(list, apply, "group", []string{"d", "a", "c"})
+	if err == nil || !strings.Contains(err.Error(), "no pending suggestions for users c, d") {
+		t.Errorf("Expected missing suggestions error, got %v", err)
+	}
+	if body != nil {
+		t.Errorf("Expected no request, got %s", body)
+	}
+
+	failing := func(groupID string) ([]UserGroupSuggestion, error) {
+		return nil, errors.New("boom")
+	}
+	if err := approveUserGroupSuggestions(failing, apply, "group", []string{"a"}); err == nil {
+		t.Error("Expected the listing error to be returned")
+	}
+}
@@ -1,11 +1,15 @@
 package jumpcloud
 
 import (
+	"context"
 	"fmt"
+	"regexp"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
 
 func TestUserGroupResourceBasic(t *testing.T) {
@@ -33,3 +37,333 @@ func testUserGroupResourceConfigBasic(name string) string {
 		}`, name,
 	)
 }
+
+func TestUserGroupResourcePosixGroup(t *testing.T) {
+	rName := acctest.RandStringFromCharSet(10, acctest.CharSetAlpha)
+	var groupID string
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: nil,
+		Steps: []resource.TestStep{
+			{
+				Config: testUserGroupResourceConfigPosixGroup(rName, "posix_a"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("jumpcloud_user_group.test_group", "attributes.0.posix_group.0.id", "4242"),
+					resource.TestCheckResourceAttr("jumpcloud_user_group.test_group", "attributes.0.posix_group.0.name", "posix_a"),
+					func(s *terraform.State) error {
+						groupID = s.RootModule().Resources["jumpcloud_user_group.test_group"].Primary.ID
+						return nil
+					},
+				),
+			},
+			{
+				// Renaming the posix group doesn't recreate the user group
+				Config: testUserGroupResourceConfigPosixGroup(rName, "posix_b"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("jumpcloud_user_group.test_group", "attributes.0.posix_group.0.name", "posix_b"),
+					func(s *terraform.State) error {
+						if id := s.RootModule().Resources["jumpcloud_user_group.test_group"].Primary.ID; id != groupID {
+							return fmt.Errorf("expected group %s to be updated in place, got %s", groupID, id)
+						}
+						return nil
+					},
+				),
+			},
+			{
+				Config:      testUserGroupResourceConfigPosixGroup(rName, "Invalid Name"),
+				ExpectError: regexp.MustCompile("must start with a lowercase letter"),
+			},
+		},
+	})
+}
+
+func testUserGroupResourceConfigPosixGroup(name, posixName string) string {
+	return fmt.Sprintf(`
+		resource "jumpcloud_user_group" "test_group" {
+			name = "%s"
+			attributes {
+				posix_group {
+					id   = 4242
+					name = "%s"
+				}
+			}
+		}`, name, posixName,
+	)
+}
+
+func TestUserGroupResourceRename(t *testing.T) {
+	rName := acctest.RandStringFromCharSet(10, acctest.CharSetAlpha)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: nil,
+		Steps: []resource.TestStep{
+			{
+				Config: testUserGroupResourceConfigBasic(rName),
+			},
+			{
+				Config: testUserGroupResourceConfigBasic(rName + "_renamed"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("jumpcloud_user_group.test_group", "name", rName+"_renamed"),
+				),
+			},
+		},
+	})
+}
+
+func TestUserGroupResourceDescription(t *testing.T) {
+	rName := acctest.RandStringFromCharSet(10, acctest.CharSetAlpha)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: nil,
+		Steps: []resource.TestStep{
+			{
+				Config: testUserGroupResourceConfigDescription(rName, "first"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("jumpcloud_user_group.test_group", "description", "first"),
+					resource.TestCheckResourceAttr("jumpcloud_user_group.test_group", "email", rName+"@testorg.com"),
+				),
+			},
+			{
+				Config: testUserGroupResourceConfigDescription(rName, "second"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("jumpcloud_user_group.test_group", "description", "second"),
+				),
+			},
+		},
+	})
+}
+
+func testUserGroupResourceConfigDescription(name, description string) string {
+	return fmt.Sprintf(`
+		resource "jumpcloud_user_group" "test_group" {
+			name        = "%[1]s"
+			description = "%[2]s"
+			email       = "%[1]s@testorg.com"
+		}`, name, description,
+	)
+}
+
+func TestUserGroupResourceDynamic(t *testing.T) {
+	rName := acctest.RandStringFromCharSet(10, acctest.CharSetAlpha)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: nil,
+		Steps: []resource.TestStep{
+			{
+				Config: testUserGroupResourceConfigDynamic(rName, ""),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("jumpcloud_user_group.test_group", "membership_method", "dynamic_automatic"),
+					resource.TestCheckResourceAttr("jumpcloud_user_group.test_group", "member_query.0.filter.0.field", "department"),
+					resource.TestCheckResourceAttr("jumpcloud_user_group.test_group", "member_query.0.filter.0.value", rName),
+				),
+			},
+			{
+				// Reading the computed members must not produce a diff
+				Config:   testUserGroupResourceConfigDynamic(rName, ""),
+				PlanOnly: true,
+			},
+			{
+				Config:      testUserGroupResourceConfigDynamic(rName, `members = ["someone@testorg.com"]`),
+				ExpectError: regexp.MustCompile("members cannot be configured"),
+			},
+		},
+	})
+}
+
+func testUserGroupResourceConfigDynamic(name, members string) string {
+	return fmt.Sprintf(`
+		resource "jumpcloud_user_group" "test_group" {
+			name              = "%[1]s"
+			membership_method = "dynamic_automatic"
+			%[2]s
+
+			member_query {
+				filter {
+					field    = "department"
+					operator = "eq"
+					value    = "%[1]s"
+				}
+			}
+		}`, name, members,
+	)
+}
+
+func TestUserGroupResourceLdapSync(t *testing.T) {
+	rName := acctest.RandStringFromCharSet(10, acctest.CharSetAlpha)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: nil,
+		Steps: []resource.TestStep{
+			{
+				Config: testUserGroupResourceConfigLdapSync(rName, `ldap_groups = ["`+rName+`"]`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("jumpcloud_user_group.test_group", "attributes.0.ldap_groups.0", rName),
+					resource.TestCheckResourceAttr("jumpcloud_user_group.test_group", "attributes.0.sudo.0.enabled", "true"),
+					resource.TestCheckResourceAttr("jumpcloud_user_group.test_group", "attributes.0.sudo.0.without_password", "true"),
+				),
+			},
+			{
+				Config:      testUserGroupResourceConfigLdapSync(rName, "enable_samba = true"),
+				ExpectError: regexp.MustCompile("enable_samba requires LDAP sync"),
+			},
+		},
+	})
+}
+
+func testUserGroupResourceConfigLdapSync(name, ldap string) string {
+	return fmt.Sprintf(`
+		resource "jumpcloud_user_group" "test_group" {
+			name = "%s"
+
+			attributes {
+				%s
+
+				sudo {
+					enabled          = true
+					without_password = true
+				}
+			}
+		}`, name, ldap,
+	)
+}
+
+func TestUserGroupResourceRadiusReply(t *testing.T) {
+	rName := acctest.RandStringFromCharSet(10, acctest.CharSetAlpha)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: nil,
+		Steps: []resource.TestStep{
+			{
+				Config: testUserGroupResourceConfigRadiusReply(rName, "Tunnel-Private-Group-Id", "42"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("jumpcloud_user_group.test_group", "attributes.0.radius_reply_attribute.#", "2"),
+					resource.TestCheckResourceAttr("jumpcloud_user_group.test_group", "attributes.0.radius_reply_attribute.1.value", "42"),
+				),
+			},
+			{
+				Config: testUserGroupResourceConfigRadiusReply(rName, "Tunnel-Private-Group-Id", "43"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("jumpcloud_user_group.test_group", "attributes.0.radius_reply_attribute.1.value", "43"),
+				),
+			},
+			{
+				Config:      testUserGroupResourceConfigRadiusReply(rName, "Tunnel-Private-Group", "43"),
+				ExpectError: regexp.MustCompile("expected attributes.0.radius_reply_attribute.1.name to be one of"),
+			},
+		},
+	})
+}
+
+func testUserGroupResourceConfigRadiusReply(name, attribute, value string) string {
+	return fmt.Sprintf(`
+		resource "jumpcloud_user_group" "test_group" {
+			name = "%s"
+
+			attributes {
+				radius_reply_attribute {
+					name  = "Tunnel-Type"
+					value = "VLAN"
+				}
+
+				radius_reply_attribute {
+					name  = "%s"
+					value = "%s"
+				}
+			}
+		}`, name, attribute, value,
+	)
+}
+
+func TestUserGroupResourceMembers(t *testing.T) {
+	rName := acctest.RandStringFromCharSet(10, acctest.CharSetAlpha)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: nil,
+		Steps: []resource.TestStep{
+			{
+				Config: testUserGroupResourceConfigMembers(rName,
+					`members = [upper(jumpcloud_user.test_user.email), jumpcloud_user.test_user.email]`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("jumpcloud_user_group.test_group", "members.#", "1"),
+					resource.TestCheckTypeSetElemAttrPair("jumpcloud_user_group.test_group", "member_ids.*",
+						"jumpcloud_user.test_user", "id"),
+				),
+			},
+			{
+				Config: testUserGroupResourceConfigMembers(rName, `member_ids = [jumpcloud_user.test_user.id]`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("jumpcloud_user_group.test_group", "member_ids.#", "1"),
+					resource.TestCheckTypeSetElemAttr("jumpcloud_user_group.test_group", "members.*", rName+"@testorg.com"),
+				),
+			},
+			{
+				Config: testUserGroupResourceConfigMembers(rName, `member_ids = []`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("jumpcloud_user_group.test_group", "member_ids.#", "0"),
+					resource.TestCheckResourceAttr("jumpcloud_user_group.test_group", "members.#", "0"),
+				),
+			},
+		},
+	})
+}
+
+func testUserGroupResourceConfigMembers(name, members string) string {
+	return fmt.Sprintf(`
+		resource "jumpcloud_user" "test_user" {
+			username = "%[1]s"
+			email    = "%[1]s@testorg.com"
+		}
+
+		resource "jumpcloud_user_group" "test_group" {
+			name = "%[1]s"
+			%[2]s
+		}`, name, members,
+	)
+}
+
+func TestUserGroupMembersCaseOnlyDiff(t *testing.T) {
+	state := &terraform.InstanceState{
+		ID: "group1",
+		Attributes: map[string]string{
+			"id":                "group1",
+			"name":              "group",
+			"membership_method": membershipMethodStatic,
+			"members.#":         "1",
+			fmt.Sprintf("members.%d", hashStringIgnoringCase("alice@testorg.com")): "alice@testorg.com",
+			"member_ids.#": "1",
+			fmt.Sprintf("member_ids.%d", schema.HashString("user1")): "user1",
+		},
+	}
+
+	for email, changed := range map[string]bool{
+		"Alice@testorg.com": false,
+		"bob@testorg.com":   true,
+	} {
+		config := terraform.NewResourceConfigRaw(map[string]interface{}{
+			"name":    "group",
+			"members": []interface{}{email},
+		})
+		diff, err := resourceUserGroup().Diff(context.Background(), state, config, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, computed := diff.GetAttribute("member_ids.#")
+		if computed != changed {
+			t.Errorf("Expected member_ids to be computed to be %t for %s, got %v", changed, email, diff)
+		}
+	}
+}
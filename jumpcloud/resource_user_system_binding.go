@@ -0,0 +1,196 @@
+package jumpcloud
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	jcapiv2 "github.com/TheJumpCloud/jcapi-go/v2"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func resourceUserSystemBinding() *schema.Resource {
+	return &schema.Resource{
+		Description: "Provides a resource for binding a JumpCloud user directly to a system, " +
+			"optionally granting sudo rights on that system only.",
+		Create: resourceUserSystemBindingCreate,
+		Read:   resourceUserSystemBindingRead,
+		Update: resourceUserSystemBindingUpdate,
+		Delete: resourceUserSystemBindingDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceUserSystemBindingImport,
+		},
+		Schema: map[string]*schema.Schema{
+			"user_id": {
+				Description: "The ID of the `jumpcloud_user` resource.",
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+			},
+			"system_id": {
+				Description: "The ID of the system to bind the user to.",
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+			},
+			"sudo": {
+				Description: "The sudo settings of the user on this system.",
+				Type:        schema.TypeList,
+				Optional:    true,
+				MaxItems:    1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"enabled": {
+							Description: "Whether the user has admin (sudo) rights on the system.",
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Default:     false,
+						},
+						"without_password": {
+							Description: "Whether sudo doesn't ask for the user's password.",
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Default:     false,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func expandSystemBindingAttributes(sudo []interface{}) *jcapiv2.SystemGraphManagementReqAttributes {
+	attr := &jcapiv2.SystemGraphManagementReqAttributes{
+		Sudo: &jcapiv2.SystemGraphManagementReqAttributesSudo{},
+	}
+	if len(sudo) == 0 || sudo[0] == nil {
+		return attr
+	}
+	settings := sudo[0].(map[string]interface{})
+	attr.Sudo.Enabled = settings["enabled"].(bool)
+	attr.Sudo.WithoutPassword = settings["without_password"].(bool)
+	return attr
+}
+
+func modifyUserSystemBinding(client *jcapiv2.APIClient, d *schema.ResourceData, action string) error {
+	payload := jcapiv2.UserGraphManagementReq{
+		Op:    action,
+		Type_: "system",
+		Id:    d.Get("system_id").(string),
+	}
+	if action != "remove" {
+		payload.Attributes = expandSystemBindingAttributes(d.Get("sudo").([]interface{}))
+	}
+
+	req := map[string]interface{}{
+		"body": payload,
+	}
+
+	res, err := client.UsersApi.GraphUserAssociationsPost(
+		context.TODO(), d.Get("user_id").(string), "", "", req)
+	if err != nil {
+		return fmt.Errorf("error managing system binding, action: %s, user id: %s, system id: %s, error: %s; response = %+v",
+			action, d.Get("user_id").(string), d.Get("system_id").(string), err, res)
+	}
+	return nil
+}
+
+func resourceUserSystemBindingCreate(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*jcapiv2.Configuration)
+	client := jcapiv2.NewAPIClient(config)
+
+	if err := modifyUserSystemBinding(client, d, "add"); err != nil {
+		return err
+	}
+
+	d.SetId(fmt.Sprintf("%s/%s", d.Get("user_id").(string), d.Get("system_id").(string)))
+
+	return resourceUserSystemBindingRead(d, meta)
+}
+
+// resourceUserSystemBindingRead consumes the JC's HTTP API directly, as the
+// SDK doesn't return the sudo attributes of an association
+func resourceUserSystemBindingRead(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*jcapiv2.Configuration)
+
+	userID := d.Get("user_id").(string)
+	systemID := d.Get("system_id").(string)
+
+	associations, res, err := listUserAssociations(httpUserAssociations(config, userID, "system"))
+	if err != nil {
+		if res != nil && res.StatusCode == http.StatusNotFound {
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+
+	for _, v := range associations {
+		if v.To == nil || v.To.Id != systemID {
+			continue
+		}
+
+		var enabled, withoutPassword bool
+		if v.Attributes != nil && v.Attributes.Sudo != nil {
+			enabled = v.Attributes.Sudo.Enabled
+			withoutPassword = v.Attributes.Sudo.WithoutPassword
+		}
+
+		// Keep the sudo block absent when it isn't configured and sudo is off
+		sudo := []interface{}{}
+		if len(d.Get("sudo").([]interface{})) > 0 || enabled || withoutPassword {
+			sudo = append(sudo, map[string]interface{}{
+				"enabled":          enabled,
+				"without_password": withoutPassword,
+			})
+		}
+		if err := d.Set("sudo", sudo); err != nil {
+			return err
+		}
+
+		d.SetId(fmt.Sprintf("%s/%s", userID, systemID))
+		return nil
+	}
+
+	// Unset the ID to remove the resource from the state
+	d.SetId("")
+	return nil
+}
+
+func resourceUserSystemBindingUpdate(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*jcapiv2.Configuration)
+	client := jcapiv2.NewAPIClient(config)
+
+	if d.HasChange("sudo") {
+		if err := modifyUserSystemBinding(client, d, "update"); err != nil {
+			return err
+		}
+	}
+
+	return resourceUserSystemBindingRead(d, meta)
+}
+
+func resourceUserSystemBindingDelete(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*jcapiv2.Configuration)
+	client := jcapiv2.NewAPIClient(config)
+
+	return modifyUserSystemBinding(client, d, "remove")
+}
+
+func resourceUserSystemBindingImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	// Expected ID format: <user_id>/<system_id>
+	idParts := strings.Split(d.Id(), "/")
+	if len(idParts) != 2 || idParts[0] == "" || idParts[1] == "" {
+		return nil, fmt.Errorf("unexpected format of ID (%s), expected <user_id>/<system_id>", d.Id())
+	}
+
+	if err := d.Set("user_id", idParts[0]); err != nil {
+		return nil, err
+	}
+	if err := d.Set("system_id", idParts[1]); err != nil {
+		return nil, err
+	}
+
+	return []*schema.ResourceData{d}, nil
+}
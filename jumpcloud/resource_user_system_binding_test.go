@@ -0,0 +1,64 @@
+package jumpcloud
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestUserSystemBindingResourceBasic(t *testing.T) {
+	systemID := os.Getenv("JUMPCLOUD_SYSTEM_ID")
+	if systemID == "" {
+		t.Skip("JUMPCLOUD_SYSTEM_ID must be set to the ID of an existing system")
+	}
+	rName := acctest.RandStringFromCharSet(10, acctest.CharSetAlpha)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: nil,
+		Steps: []resource.TestStep{
+			{
+				Config: testUserSystemBindingResourceConfig(rName, systemID, false),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("jumpcloud_user_system_binding.test_binding", "system_id", systemID),
+					resource.TestCheckResourceAttr("jumpcloud_user_system_binding.test_binding", "sudo.0.enabled", "true"),
+					resource.TestCheckResourceAttr("jumpcloud_user_system_binding.test_binding", "sudo.0.without_password", "false"),
+				),
+			},
+			{
+				Config: testUserSystemBindingResourceConfig(rName, systemID, true),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("jumpcloud_user_system_binding.test_binding", "sudo.0.without_password", "true"),
+				),
+			},
+			{
+				ResourceName:      "jumpcloud_user_system_binding.test_binding",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testUserSystemBindingResourceConfig(name, systemID string, withoutPassword bool) string {
+	return fmt.Sprintf(`
+resource "jumpcloud_user" "test_user" {
+  username = "%[1]s"
+  email    = "%[1]s@testorg.com"
+}
+
+resource "jumpcloud_user_system_binding" "test_binding" {
+  user_id   = jumpcloud_user.test_user.id
+  system_id = "%[2]s"
+
+  sudo {
+    enabled          = true
+    without_password = %[3]t
+  }
+}
+`, name, systemID, withoutPassword)
+}
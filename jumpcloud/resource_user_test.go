@@ -1,11 +1,19 @@
 package jumpcloud
 
 import (
+	"bytes"
+	"context"
 	"fmt"
+	"log"
+	"os"
+	"reflect"
+	"regexp"
+	"strings"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
 
 func TestUserResourceBasic(t *testing.T) {
@@ -192,3 +200,257 @@ func TestUserResourceImportWithGroups(t *testing.T) {
 		},
 	})
 }
+
+func TestUserResourceDuplicateEmail(t *testing.T) {
+	rName := acctest.RandStringFromCharSet(10, acctest.CharSetAlpha)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: nil,
+		Steps: []resource.TestStep{
+			{
+				Config: testUserResourceConfigBasic(rName),
+			},
+			{
+				Config:      testUserResourceConfigDuplicateEmail(rName),
+				ExpectError: regexp.MustCompile(`email "` + rName + `@testorg.com" is already used by user`),
+			},
+		},
+	})
+}
+
+func testUserResourceConfigDuplicateEmail(name string) string {
+	return testUserResourceConfigBasic(name) + fmt.Sprintf(`
+		resource "jumpcloud_user" "duplicate" {
+			username = "%[1]s_duplicate"
+			email    = "%[1]s@testorg.com"
+		}`, name,
+	)
+}
+
+func TestUserResourceWithGroupNames(t *testing.T) {
+	rName := acctest.RandStringFromCharSet(10, acctest.CharSetAlpha)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: nil,
+		Steps: []resource.TestStep{
+			{
+				Config: testUserResourceConfigWithGroupNames(rName),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("jumpcloud_user.test_user", "group_names.#", "2"),
+					resource.TestCheckResourceAttr("jumpcloud_user.test_user", "groups.#", "2"),
+					resource.TestCheckResourceAttrPair("jumpcloud_user.test_user", "group_name_ids.test_group_1_"+rName,
+						"jumpcloud_user_group.test_group_1", "id"),
+				),
+			},
+		},
+	})
+}
+
+func testUserResourceConfigWithGroupNames(name string) string {
+	return fmt.Sprintf(`
+		resource "jumpcloud_user_group" "test_group_1" {
+			name = "test_group_1_%[1]s"
+		}
+
+		resource "jumpcloud_user_group" "test_group_2" {
+			name = "test_group_2_%[1]s"
+		}
+
+		resource "jumpcloud_user" "test_user" {
+			username    = "%[1]s"
+			email       = "%[1]s@testorg.com"
+			group_names = [
+				jumpcloud_user_group.test_group_1.name,
+				jumpcloud_user_group.test_group_2.name,
+			]
+		}
+	`, name)
+}
+
+func TestUserResourceAdditiveGroups(t *testing.T) {
+	rName := acctest.RandStringFromCharSet(10, acctest.CharSetAlpha)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: nil,
+		Steps: []resource.TestStep{
+			{
+				Config: testUserResourceConfigAdditiveGroups(rName),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("jumpcloud_user.test_user", "groups_mode", "additive"),
+					resource.TestCheckResourceAttr("jumpcloud_user.test_user", "groups.#", "1"),
+					resource.TestCheckResourceAttr("jumpcloud_user.test_user", "managed_groups.#", "1"),
+				),
+			},
+		},
+	})
+}
+
+func testUserResourceConfigAdditiveGroups(name string) string {
+	return fmt.Sprintf(`
+		resource "jumpcloud_user_group" "test_group_1" {
+			name = "test_group_1_%[1]s"
+		}
+
+		resource "jumpcloud_user_group" "test_group_2" {
+			name = "test_group_2_%[1]s"
+		}
+
+		resource "jumpcloud_user" "test_user" {
+			username    = "%[1]s"
+			email       = "%[1]s@testorg.com"
+			groups_mode = "additive"
+			groups      = [
+				jumpcloud_user_group.test_group_1.id,
+			]
+		}
+
+		# Membership managed outside of the user resource
+		resource "jumpcloud_user_group_membership" "test_membership" {
+			userid  = jumpcloud_user.test_user.id
+			groupid = jumpcloud_user_group.test_group_2.id
+		}
+	`, name)
+}
+
+func TestUserResourceLdapServers(t *testing.T) {
+	ldapServerID := os.Getenv("JUMPCLOUD_LDAP_SERVER_ID")
+	if ldapServerID == "" {
+		t.Skip("JUMPCLOUD_LDAP_SERVER_ID must be set to the ID of an existing LDAP server")
+	}
+	rName := acctest.RandStringFromCharSet(10, acctest.CharSetAlpha)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: nil,
+		Steps: []resource.TestStep{
+			{
+				Config: testUserResourceConfigLdapServers(rName, `["`+ldapServerID+`"]`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("jumpcloud_user.test_user", "ldap_servers.#", "1"),
+					resource.TestCheckResourceAttr("jumpcloud_user.test_user", "ldap_binding_user", "true"),
+				),
+			},
+			{
+				// Unbinding the last server
+				Config: testUserResourceConfigLdapServers(rName, "[]"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("jumpcloud_user.test_user", "ldap_servers.#", "0"),
+				),
+			},
+		},
+	})
+}
+
+func testUserResourceConfigLdapServers(name, ldapServers string) string {
+	return fmt.Sprintf(`
+		resource "jumpcloud_user" "test_user" {
+			username     = "%[1]s"
+			email        = "%[1]s@testorg.com"
+			ldap_servers = %[2]s
+		}
+	`, name, ldapServers)
+}
+
+func TestUserResourceActivationEmail(t *testing.T) {
+	rName := acctest.RandStringFromCharSet(10, acctest.CharSetAlpha)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: nil,
+		Steps: []resource.TestStep{
+			{
+				Config: testUserResourceConfigActivationEmail(rName, ""),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("jumpcloud_user.test_user", "activation_email", "send"),
+					resource.TestCheckResourceAttr("jumpcloud_user.test_user", "activated", "false"),
+				),
+			},
+			{
+				Config: testUserResourceConfigActivationEmail(rName, "1"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("jumpcloud_user.test_user", "reset_password_trigger", "1"),
+				),
+			},
+		},
+	})
+}
+
+func testUserResourceConfigActivationEmail(name, trigger string) string {
+	return fmt.Sprintf(`
+		resource "jumpcloud_user" "test_user" {
+			username               = "%[1]s"
+			email                  = "%[1]s@testorg.com"
+			activation_email       = "send"
+			reset_password_trigger = "%[2]s"
+		}
+	`, name, trigger)
+}
+
+func TestResolveNewGroupNames(t *testing.T) {
+	// "ops" was renamed in the console, so it can't be looked up anymore
+	known := map[string]interface{}{"dev": "g-dev", "ops": "g-ops"}
+	var looked []string
+	resolve := func(names []string) (map[string]string, error) {
+		looked = append(looked, names...)
+		result := make(map[string]string)
+		for _, name := range names {
+			if name != "hr" {
+				return nil, &groupsNotFoundError{names: []string{name}}
+			}
+			result[name] = "g-hr"
+		}
+		return result, nil
+	}
+
+	nameToID, err := resolveNewGroupNames([]string{"dev", "ops", "hr"}, known, resolve)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]string{"dev": "g-dev", "ops": "g-ops", "hr": "g-hr"}
+	if !reflect.DeepEqual(nameToID, expected) {
+		t.Errorf("Expected %v, got %v", expected, nameToID)
+	}
+	if !reflect.DeepEqual(looked, []string{"hr"}) {
+		t.Errorf("Expected only the new name to be looked up, got %v", looked)
+	}
+
+	looked = nil
+	if _, err := resolveNewGroupNames([]string{"dev"}, known, resolve); err != nil || len(looked) != 0 {
+		t.Errorf("Expected known names not to be looked up, got %v (%v)", looked, err)
+	}
+}
+
+func TestWarnUnboundLdapBindingUser(t *testing.T) {
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer log.SetOutput(os.Stderr)
+
+	for _, tc := range []struct {
+		config map[string]interface{}
+		warned bool
+	}{
+		{map[string]interface{}{"ldap_binding_user": true}, true},
+		{map[string]interface{}{"ldap_binding_user": true, "ldap_servers": []interface{}{"ldap1"}}, false},
+		{map[string]interface{}{"ldap_servers": []interface{}{"ldap1"}}, false},
+		{map[string]interface{}{}, false},
+	} {
+		buf.Reset()
+		tc.config["username"] = "john.doe"
+		tc.config["email"] = "john.doe@testorg.com"
+		config := terraform.NewResourceConfigRaw(tc.config)
+		if _, err := resourceUser().Diff(context.Background(), nil, config, nil); err != nil {
+			t.Fatal(err)
+		}
+		if warned := strings.Contains(buf.String(), "isn't bound to any LDAP server"); warned != tc.warned {
+			t.Errorf("Expected warning %t for %v, got log %q", tc.warned, tc.config, buf.String())
+		}
+	}
+}
@@ -1,6 +1,8 @@
 package jumpcloud
 
 import (
+	"sort"
+
 	jcapiv1 "github.com/TheJumpCloud/jcapi-go/v1"
 )
 
@@ -37,3 +39,88 @@ func expandPhoneNumbers(input []interface{}) []map[string]string {
 	}
 	return phoneNumbers
 }
+
+// restrictedUserFieldAttributes maps the JumpCloud names used in a user's
+// restrictedFields to the attributes of the jumpcloud_user resource
+var restrictedUserFieldAttributes = map[string]string{
+	"username":                       "username",
+	"email":                          "email",
+	"firstname":                      "firstname",
+	"lastname":                       "lastname",
+	"displayname":                    "display_name",
+	"password":                       "password",
+	"enable_user_portal_multifactor": "enable_mfa",
+	"ldap_binding_user":              "ldap_binding_user",
+	"password_never_expires":         "password_never_expires",
+	"sudo":                           "sudo",
+	"suspended":                      "suspended",
+	"phoneNumbers":                   "phone_number",
+}
+
+// sortedRestrictedAttributes returns the attributes of restrictedUserAttributes
+// in order
+func sortedRestrictedAttributes(restricted map[string]bool) []string {
+	attrs := make([]string, 0, len(restricted))
+	for attr := range restricted {
+		attrs = append(attrs, attr)
+	}
+	sort.Strings(attrs)
+	return attrs
+}
+
+func flattenRestrictedFields(rf []SystemUserRestrictedField) []interface{} {
+	fields := make([]interface{}, 0, len(rf))
+	for _, v := range rf {
+		if v.Field != "" {
+			fields = append(fields, v.Field)
+		}
+	}
+	return fields
+}
+
+// restrictedUserAttributes returns the resource attributes that correspond
+// to the given JumpCloud restricted fields. Fields the resource doesn't
+// manage are ignored.
+func restrictedUserAttributes(fields []interface{}) map[string]bool {
+	attrs := make(map[string]bool)
+	for _, v := range fields {
+		if attr, ok := restrictedUserFieldAttributes[v.(string)]; ok {
+			attrs[attr] = true
+		}
+	}
+	return attrs
+}
+
+// omitRestrictedUserFields clears the restricted attributes from an update
+// payload. All fields of jcapiv1.Systemuserput are omitempty, so a zero
+// value leaves the field untouched on the JumpCloud side.
+func omitRestrictedUserFields(payload *jcapiv1.Systemuserput, restricted map[string]bool) {
+	for attr := range restricted {
+		switch attr {
+		case "username":
+			payload.Username = ""
+		case "email":
+			payload.Email = ""
+		case "firstname":
+			payload.Firstname = ""
+		case "lastname":
+			payload.Lastname = ""
+		case "display_name":
+			payload.Displayname = ""
+		case "password":
+			payload.Password = ""
+		case "enable_mfa":
+			payload.EnableUserPortalMultifactor = false
+		case "ldap_binding_user":
+			payload.LdapBindingUser = false
+		case "password_never_expires":
+			payload.PasswordNeverExpires = false
+		case "sudo":
+			payload.Sudo = false
+		case "suspended":
+			payload.Suspended = false
+		case "phone_number":
+			payload.PhoneNumbers = nil
+		}
+	}
+}
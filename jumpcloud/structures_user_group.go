@@ -3,79 +3,218 @@ package jumpcloud
 // see https://www.terraform.io/docs/extend/writing-custom-providers.html#implementing-a-more-complex-read
 
 import (
-	"fmt"
+	"log"
 	"strconv"
 	"strings"
 
 	jcapiv2 "github.com/TheJumpCloud/jcapi-go/v2"
 )
 
-func flattenAttributes(attr *jcapiv2.UserGroupAttributes) []interface{} {
+func flattenAttributes(attr *UserGroupAttributes) []interface{} {
+	ldapGroups := make([]interface{}, 0, len(attr.LdapGroups))
+	for _, v := range attr.LdapGroups {
+		ldapGroups = append(ldapGroups, v.Name)
+	}
 	return []interface{}{
 		map[string]interface{}{
-			"posix_groups": flattenPosixGroups(attr.PosixGroups),
-			// "enable_samba": fmt.Sprintf("%t", attr.SambaEnabled),
+			"posix_group":            flattenPosixGroups(attr.PosixGroups),
+			"ldap_groups":            ldapGroups,
+			"enable_samba":           attr.SambaEnabled,
+			"sudo":                   flattenUserGroupSudo(attr.Sudo),
+			"radius_reply_attribute": flattenRadiusReplyAttributes(attr.Radius),
 		},
 	}
 }
 
-func flattenPosixGroups(pg []jcapiv2.UserGroupAttributesPosixGroups) string {
-	out := []string{}
+func flattenRadiusReplyAttributes(radius *UserGroupRadius) []interface{} {
+	if radius == nil {
+		return []interface{}{}
+	}
+	out := make([]interface{}, 0, len(radius.Reply))
+	for _, v := range radius.Reply {
+		out = append(out, map[string]interface{}{
+			"name":  v.Name,
+			"value": v.Value,
+		})
+	}
+	return out
+}
+
+// flattenUserGroupSudo returns no sudo block for disabled sudo, so that
+// groups without the block don't show a diff
+func flattenUserGroupSudo(sudo *UserGroupSudo) []interface{} {
+	if sudo == nil || (!sudo.Enabled && !sudo.WithoutPassword) {
+		return []interface{}{}
+	}
+	return []interface{}{
+		map[string]interface{}{
+			"enabled":          sudo.Enabled,
+			"without_password": sudo.WithoutPassword,
+		},
+	}
+}
+
+func flattenPosixGroups(pg []jcapiv2.UserGroupAttributesPosixGroups) []interface{} {
+	out := make([]interface{}, 0, len(pg))
 	for _, v := range pg {
-		out = append(out, fmt.Sprintf("%d:%s", v.Id, v.Name))
+		out = append(out, map[string]interface{}{
+			"id":   int(v.Id),
+			"name": v.Name,
+		})
 	}
-	return strings.Join(out, ",")
+	return out
 }
 
-func expandAttributes(attr interface{}) (out *jcapiv2.UserGroupAttributes, ok bool) {
+func expandAttributes(attr interface{}) (out *UserGroupAttributes, ok bool) {
 	if attr == nil {
 		return
 	}
 	// Handle list input (TypeList with MaxItems: 1)
 	listAttr, ok := attr.([]interface{})
 	if !ok || len(listAttr) == 0 {
-		return
+		return nil, false
 	}
 	mapAttr, ok := listAttr[0].(map[string]interface{})
 	if !ok {
-		return
+		return nil, false
 	}
 
-	// var enableSamba bool
-	// sambaStr, ok := mapAttr["enable_samba"].(string)
-	// if ok {
-	// 	enableSamba, _ = strconv.ParseBool(sambaStr)
-	// }
+	out = &UserGroupAttributes{
+		PosixGroups: expandPosixGroups(mapAttr["posix_group"]),
+		Sudo:        expandUserGroupSudo(mapAttr["sudo"]),
+		Radius:      expandRadiusReplyAttributes(mapAttr["radius_reply_attribute"]),
+	}
+	out.SambaEnabled, _ = mapAttr["enable_samba"].(bool)
+	ldapGroups, _ := mapAttr["ldap_groups"].([]interface{})
+	for _, v := range ldapGroups {
+		if name, ok := v.(string); ok && name != "" {
+			out.LdapGroups = append(out.LdapGroups, UserGroupLdapGroup{Name: name})
+		}
+	}
 
-	// TODO: empty string? nil?
-	posixStr, ok := mapAttr["posix_groups"].(string)
-	if !ok {
-		return
+	if len(out.PosixGroups) == 0 && len(out.LdapGroups) == 0 && !out.SambaEnabled && out.Sudo == nil &&
+		out.Radius == nil {
+		return nil, false
+	}
+	return out, true
+}
+
+func expandUserGroupSudo(sudo interface{}) *UserGroupSudo {
+	list, _ := sudo.([]interface{})
+	if len(list) == 0 || list[0] == nil {
+		return nil
+	}
+	mapSudo := list[0].(map[string]interface{})
+	return &UserGroupSudo{
+		Enabled:         mapSudo["enabled"].(bool),
+		WithoutPassword: mapSudo["without_password"].(bool),
+	}
+}
+
+func expandRadiusReplyAttributes(attrs interface{}) *UserGroupRadius {
+	list, _ := attrs.([]interface{})
+	if len(list) == 0 {
+		return nil
 	}
+	radius := &UserGroupRadius{
+		Reply: make([]UserGroupRadiusReply, 0, len(list)),
+	}
+	for _, v := range list {
+		attr, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		radius.Reply = append(radius.Reply, UserGroupRadiusReply{
+			Name:  attr["name"].(string),
+			Value: attr["value"].(string),
+		})
+	}
+	return radius
+}
 
-	groups := strings.Split(posixStr, ",")
-	posixGroups := []jcapiv2.UserGroupAttributesPosixGroups{}
-	for _, v := range groups {
-		g := strings.Split(v, ":")
+func expandPosixGroups(pg interface{}) []jcapiv2.UserGroupAttributesPosixGroups {
+	list, _ := pg.([]interface{})
+	posixGroups := make([]jcapiv2.UserGroupAttributesPosixGroups, 0, len(list))
+	for _, v := range list {
+		group, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		posixGroups = append(posixGroups, jcapiv2.UserGroupAttributesPosixGroups{
+			Id:   int32(group["id"].(int)),
+			Name: group["name"].(string),
+		})
+	}
+	return posixGroups
+}
+
+// parsePosixGroupsString parses the "id:name,id:name" form of posix groups
+// used before the posix_group block. Malformed pairs are skipped.
+func parsePosixGroupsString(posixStr string) []interface{} {
+	out := []interface{}{}
+	if posixStr == "" {
+		return out
+	}
+	for _, v := range strings.Split(posixStr, ",") {
+		g := strings.SplitN(strings.TrimSpace(v), ":", 2)
 		if len(g) != 2 {
-			return
+			log.Printf("[WARN] Skipping malformed posix group %q", v)
+			continue
 		}
-		id, err := strconv.ParseInt(g[0], 10, 32)
+		id, err := strconv.Atoi(g[0])
 		if err != nil {
+			log.Printf("[WARN] Skipping posix group %q with invalid id: %s", v, err)
 			continue
 		}
-		posixGroups = append(posixGroups,
-			jcapiv2.UserGroupAttributesPosixGroups{
-				Id: int32(id), Name: g[1],
-			})
+		out = append(out, map[string]interface{}{
+			"id":   id,
+			"name": g[1],
+		})
+	}
+	return out
+}
+
+func flattenMemberQuery(query *UserGroupMemberQuery) []interface{} {
+	if query == nil {
+		return []interface{}{}
+	}
+	filters := make([]interface{}, 0, len(query.Filters))
+	for _, f := range query.Filters {
+		filters = append(filters, map[string]interface{}{
+			"field":    f.Field,
+			"operator": f.Operator,
+			"value":    f.Value,
+		})
+	}
+	return []interface{}{
+		map[string]interface{}{
+			"filter": filters,
+		},
 	}
+}
 
-	if len(posixGroups) == 0 {
-		return
+func expandMemberQuery(query interface{}) *UserGroupMemberQuery {
+	list, ok := query.([]interface{})
+	if !ok || len(list) == 0 || list[0] == nil {
+		return nil
 	}
+	mapQuery := list[0].(map[string]interface{})
+	filterList, _ := mapQuery["filter"].([]interface{})
 
-	return &jcapiv2.UserGroupAttributes{
-		PosixGroups: posixGroups,
-		// SambaEnabled: enableSamba,
-	}, true
+	filters := make([]UserGroupMemberQueryFilter, 0, len(filterList))
+	for _, f := range filterList {
+		filter, ok := f.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		filters = append(filters, UserGroupMemberQueryFilter{
+			Field:    filter["field"].(string),
+			Operator: filter["operator"].(string),
+			Value:    filter["value"].(string),
+		})
+	}
+	return &UserGroupMemberQuery{
+		QueryType: "FilterQuery",
+		Filters:   filters,
+	}
 }
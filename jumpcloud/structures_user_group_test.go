@@ -0,0 +1,241 @@
+package jumpcloud
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	jcapiv2 "github.com/TheJumpCloud/jcapi-go/v2"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestParsePosixGroupsString(t *testing.T) {
+	groups := parsePosixGroupsString("32:testerino, 33:other,bad,x:invalid")
+
+	expected := []interface{}{
+		map[string]interface{}{"id": 32, "name": "testerino"},
+		map[string]interface{}{"id": 33, "name": "other"},
+	}
+	if !reflect.DeepEqual(groups, expected) {
+		t.Errorf("Expected %v, got %v", expected, groups)
+	}
+
+	if groups := parsePosixGroupsString(""); len(groups) != 0 {
+		t.Errorf("Expected no groups, got %v", groups)
+	}
+}
+
+func TestUserGroupStateUpgradeV0(t *testing.T) {
+	state, err := resourceUserGroupStateUpgradeV0(context.Background(), map[string]interface{}{
+		"name": "group",
+		"attributes": []interface{}{
+			map[string]interface{}{"posix_groups": "32:testerino"},
+		},
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []interface{}{
+		map[string]interface{}{
+			"posix_group": []interface{}{
+				map[string]interface{}{"id": 32, "name": "testerino"},
+			},
+		},
+	}
+	if !reflect.DeepEqual(state["attributes"], expected) {
+		t.Errorf("Expected %v, got %v", expected, state["attributes"])
+	}
+}
+
+func TestUserGroupStateUpgradeV1(t *testing.T) {
+	state, err := resourceUserGroupStateUpgradeV1(context.Background(), map[string]interface{}{
+		"name":    "group",
+		"members": []interface{}{"Jane@testorg.com", "bob@testorg.com", "jane@testorg.com"},
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []interface{}{"Jane@testorg.com", "bob@testorg.com"}
+	if !reflect.DeepEqual(state["members"], expected) {
+		t.Errorf("Expected %v, got %v", expected, state["members"])
+	}
+
+	members := schema.NewSet(hashStringIgnoringCase, state["members"].([]interface{}))
+	if !members.Contains("JANE@testorg.com") {
+		t.Error("Expected members to be compared case-insensitively")
+	}
+}
+
+func TestUserGroupPatchBody(t *testing.T) {
+	current := map[string]interface{}{
+		"sambaEnabled": true,
+		"posixGroups":  []interface{}{map[string]interface{}{"id": 32, "name": "old"}},
+	}
+
+	d := schema.TestResourceDataRaw(t, resourceUserGroup().Schema, map[string]interface{}{
+		"name": "renamed",
+	})
+	body := userGroupPatchBody(d, current)
+	expected := map[string]interface{}{
+		"name":       "renamed",
+		"attributes": current,
+	}
+	if !reflect.DeepEqual(body, expected) {
+		t.Errorf("Expected unmanaged attributes to be preserved, got %v", body)
+	}
+
+	d = schema.TestResourceDataRaw(t, resourceUserGroup().Schema, map[string]interface{}{
+		"name": "renamed",
+		"attributes": []interface{}{
+			map[string]interface{}{
+				"posix_group": []interface{}{
+					map[string]interface{}{"id": 32, "name": "new"},
+				},
+			},
+		},
+	})
+	attributes := userGroupPatchBody(d, current)["attributes"].(map[string]interface{})
+	if attributes["sambaEnabled"] != true {
+		t.Errorf("Expected sambaEnabled to be preserved, got %v", attributes)
+	}
+	posixGroups := attributes["posixGroups"].([]jcapiv2.UserGroupAttributesPosixGroups)
+	if len(posixGroups) != 1 || posixGroups[0].Name != "new" {
+		t.Errorf("Expected the configured posix group, got %v", posixGroups)
+	}
+
+	d = schema.TestResourceDataRaw(t, resourceUserGroup().Schema, map[string]interface{}{
+		"name": "plain",
+	})
+	if _, ok := userGroupPatchBody(d, nil)["attributes"]; ok {
+		t.Error("Expected no attributes for a group without attributes")
+	}
+}
+
+func TestMemberQuery(t *testing.T) {
+	configured := []interface{}{
+		map[string]interface{}{
+			"filter": []interface{}{
+				map[string]interface{}{"field": "department", "operator": "eq", "value": "Sales"},
+			},
+		},
+	}
+
+	query := expandMemberQuery(configured)
+	expected := &UserGroupMemberQuery{
+		QueryType: "FilterQuery",
+		Filters: []UserGroupMemberQueryFilter{
+			{Field: "department", Operator: "eq", Value: "Sales"},
+		},
+	}
+	if !reflect.DeepEqual(query, expected) {
+		t.Errorf("Expected %v, got %v", expected, query)
+	}
+	if flattened := flattenMemberQuery(query); !reflect.DeepEqual(flattened, configured) {
+		t.Errorf("Expected %v, got %v", configured, flattened)
+	}
+
+	if query := expandMemberQuery([]interface{}{}); query != nil {
+		t.Errorf("Expected no query, got %v", query)
+	}
+	if flattened := flattenMemberQuery(nil); len(flattened) != 0 {
+		t.Errorf("Expected no query, got %v", flattened)
+	}
+}
+
+func TestFlattenMembershipMethod(t *testing.T) {
+	for method, expected := range map[string]string{
+		"":                        membershipMethodStatic,
+		"STATIC":                  membershipMethodStatic,
+		"DYNAMIC_REVIEW_REQUIRED": membershipMethodDynamicReview,
+		"DYNAMIC_AUTOMATED":       membershipMethodDynamicAutomatic,
+	} {
+		if got := flattenMembershipMethod(method); got != expected {
+			t.Errorf("Expected %q for %q, got %q", expected, method, got)
+		}
+	}
+}
+
+func TestUserGroupAttributes(t *testing.T) {
+	configured := []interface{}{
+		map[string]interface{}{
+			"posix_group":  []interface{}{},
+			"ldap_groups":  []interface{}{"developers"},
+			"enable_samba": true,
+			"sudo": []interface{}{
+				map[string]interface{}{"enabled": true, "without_password": false},
+			},
+			"radius_reply_attribute": []interface{}{
+				map[string]interface{}{"name": "Tunnel-Private-Group-Id", "value": "42"},
+			},
+		},
+	}
+
+	attr, ok := expandAttributes(configured)
+	if !ok {
+		t.Fatal("Expected attributes without posix groups to be expanded")
+	}
+	expected := &UserGroupAttributes{
+		PosixGroups:  []jcapiv2.UserGroupAttributesPosixGroups{},
+		SambaEnabled: true,
+		LdapGroups:   []UserGroupLdapGroup{{Name: "developers"}},
+		Sudo:         &UserGroupSudo{Enabled: true},
+		Radius: &UserGroupRadius{
+			Reply: []UserGroupRadiusReply{{Name: "Tunnel-Private-Group-Id", Value: "42"}},
+		},
+	}
+	if !reflect.DeepEqual(attr, expected) {
+		t.Errorf("Expected %v, got %v", expected, attr)
+	}
+	if flattened := flattenAttributes(attr); !reflect.DeepEqual(flattened, configured) {
+		t.Errorf("Expected %v, got %v", configured, flattened)
+	}
+
+	flattened := flattenAttributes(&UserGroupAttributes{Sudo: &UserGroupSudo{}})
+	if sudo := flattened[0].(map[string]interface{})["sudo"].([]interface{}); len(sudo) != 0 {
+		t.Errorf("Expected no sudo block for disabled sudo, got %v", sudo)
+	}
+}
+
+func TestUserGroupPatchBodyLdapSync(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceUserGroup().Schema, map[string]interface{}{
+		"name": "developers",
+		"attributes": []interface{}{
+			map[string]interface{}{
+				"ldap_groups":  []interface{}{"developers"},
+				"enable_samba": true,
+			},
+		},
+	})
+	attributes := userGroupPatchBody(d, nil)["attributes"].(map[string]interface{})
+
+	expected := map[string]interface{}{
+		"ldapGroups":   []UserGroupLdapGroup{{Name: "developers"}},
+		"sambaEnabled": true,
+	}
+	if !reflect.DeepEqual(attributes, expected) {
+		t.Errorf("Expected %v, got %v", expected, attributes)
+	}
+}
+
+func TestUserGroupPatchBodyRadius(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceUserGroup().Schema, map[string]interface{}{
+		"name": "wifi",
+		"attributes": []interface{}{
+			map[string]interface{}{
+				"radius_reply_attribute": []interface{}{
+					map[string]interface{}{"name": "Tunnel-Type", "value": "VLAN"},
+				},
+			},
+		},
+	})
+	attributes := userGroupPatchBody(d, nil)["attributes"].(map[string]interface{})
+
+	expected := &UserGroupRadius{
+		Reply: []UserGroupRadiusReply{{Name: "Tunnel-Type", Value: "VLAN"}},
+	}
+	if !reflect.DeepEqual(attributes["radius"], expected) {
+		t.Errorf("Expected %v, got %v", expected, attributes["radius"])
+	}
+}
@@ -0,0 +1,74 @@
+package jumpcloud
+
+import (
+	"reflect"
+	"testing"
+
+	jcapiv1 "github.com/TheJumpCloud/jcapi-go/v1"
+)
+
+func TestRestrictedUserAttributes(t *testing.T) {
+	attrs := restrictedUserAttributes([]interface{}{"firstname", "displayname", "phoneNumbers", "jobTitle"})
+
+	for _, attr := range []string{"firstname", "display_name", "phone_number"} {
+		if !attrs[attr] {
+			t.Errorf("Expected %s to be restricted", attr)
+		}
+	}
+	if len(attrs) != 3 {
+		t.Errorf("Expected unmanaged fields to be ignored, got %v", attrs)
+	}
+}
+
+func TestOmitRestrictedUserFields(t *testing.T) {
+	payload := jcapiv1.Systemuserput{
+		Username:  "john.doe",
+		Email:     "john.doe@acme.org",
+		Firstname: "John",
+		Lastname:  "Doe",
+		Sudo:      true,
+		PhoneNumbers: []jcapiv1.SystemuserputPhoneNumbers{
+			{Number: "123", Type_: "work"},
+		},
+	}
+
+	omitRestrictedUserFields(&payload, map[string]bool{
+		"firstname":    true,
+		"sudo":         true,
+		"phone_number": true,
+	})
+
+	if payload.Firstname != "" || payload.Sudo || payload.PhoneNumbers != nil {
+		t.Errorf("Expected restricted fields to be cleared, got %+v", payload)
+	}
+	if payload.Username != "john.doe" || payload.Lastname != "Doe" {
+		t.Errorf("Expected unrestricted fields to be kept, got %+v", payload)
+	}
+}
+
+func TestOmitRestrictedUserFieldsCoversAllAttributes(t *testing.T) {
+	full := jcapiv1.Systemuserput{
+		Username:                    "john.doe",
+		Email:                       "john.doe@acme.org",
+		Firstname:                   "John",
+		Lastname:                    "Doe",
+		Displayname:                 "John Doe",
+		Password:                    "secret",
+		EnableUserPortalMultifactor: true,
+		LdapBindingUser:             true,
+		PasswordNeverExpires:        true,
+		Sudo:                        true,
+		Suspended:                   true,
+		PhoneNumbers: []jcapiv1.SystemuserputPhoneNumbers{
+			{Number: "123", Type_: "work"},
+		},
+	}
+
+	for field, attr := range restrictedUserFieldAttributes {
+		payload := full
+		omitRestrictedUserFields(&payload, map[string]bool{attr: true})
+		if reflect.DeepEqual(payload, full) {
+			t.Errorf("Expected restricted field %s (%s) to be omitted from updates", field, attr)
+		}
+	}
+}
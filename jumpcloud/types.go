@@ -1,6 +1,9 @@
 package jumpcloud
 
-import jcapiv2 "github.com/TheJumpCloud/jcapi-go/v2"
+import (
+	jcapiv1 "github.com/TheJumpCloud/jcapi-go/v1"
+	jcapiv2 "github.com/TheJumpCloud/jcapi-go/v2"
+)
 
 // UserGroup is like jcapiv2.UserGroup with Attributes
 type UserGroup struct {
@@ -11,6 +14,187 @@ type UserGroup struct {
 	Type string `json:"type,omitempty"`
 
 	// Display name of a User Group.
-	Name       string                      `json:"name,omitempty"`
-	Attributes jcapiv2.UserGroupAttributes `json:"attributes,omitempty"`
+	Name       string              `json:"name,omitempty"`
+	Attributes UserGroupAttributes `json:"attributes,omitempty"`
+
+	// Description of a User Group.
+	Description string `json:"description,omitempty"`
+
+	// Email address of a User Group.
+	Email string `json:"email,omitempty"`
+
+	// MemberQuery selects the members of a dynamic User Group.
+	MemberQuery *UserGroupMemberQuery `json:"memberQuery,omitempty"`
+
+	// MembershipMethod is STATIC, DYNAMIC_REVIEW_REQUIRED or DYNAMIC_AUTOMATED.
+	MembershipMethod string `json:"membershipMethod,omitempty"`
+
+	// RawAttributes holds all attributes of the group, including the ones
+	// the SDK doesn't model, so that updates can preserve them.
+	RawAttributes map[string]interface{} `json:"-"`
+}
+
+// UserGroupPost is like jcapiv2.UserGroupPost with the description and email
+type UserGroupPost struct {
+	Attributes *UserGroupAttributes `json:"attributes,omitempty"`
+
+	// Display name of a User Group.
+	Name string `json:"name"`
+
+	// Description of a User Group.
+	Description string `json:"description,omitempty"`
+
+	// Email address of a User Group.
+	Email string `json:"email,omitempty"`
+
+	// MemberQuery selects the members of a dynamic User Group.
+	MemberQuery *UserGroupMemberQuery `json:"memberQuery,omitempty"`
+
+	// MembershipMethod is STATIC, DYNAMIC_REVIEW_REQUIRED or DYNAMIC_AUTOMATED.
+	MembershipMethod string `json:"membershipMethod,omitempty"`
+}
+
+// UserGroupAttributes is like jcapiv2.UserGroupAttributes with the LDAP
+// sync and sudo settings
+type UserGroupAttributes struct {
+	PosixGroups []jcapiv2.UserGroupAttributesPosixGroups `json:"posixGroups,omitempty"`
+
+	// SambaEnabled requires LdapGroups.
+	SambaEnabled bool `json:"sambaEnabled,omitempty"`
+
+	// LdapGroups the User Group is synced to in the JumpCloud LDAP directory.
+	LdapGroups []UserGroupLdapGroup `json:"ldapGroups,omitempty"`
+
+	// Sudo grants the members admin rights on the systems bound to the group.
+	Sudo *UserGroupSudo `json:"sudo,omitempty"`
+
+	// Radius holds the attributes JumpCloud RADIUS returns for the members.
+	Radius *UserGroupRadius `json:"radius,omitempty"`
+}
+
+// UserGroupLdapGroup is a single entry of UserGroupAttributes.LdapGroups
+type UserGroupLdapGroup struct {
+	// Name of the group in the LDAP directory.
+	Name string `json:"name"`
+}
+
+// UserGroupSudo is UserGroupAttributes.Sudo
+type UserGroupSudo struct {
+	Enabled         bool `json:"enabled"`
+	WithoutPassword bool `json:"withoutPassword"`
+}
+
+// UserGroupRadius is UserGroupAttributes.Radius
+type UserGroupRadius struct {
+	Reply []UserGroupRadiusReply `json:"reply"`
+}
+
+// UserGroupRadiusReply is a RADIUS attribute added to Access-Accept replies,
+// e.g. Tunnel-Private-Group-Id for VLAN assignment
+type UserGroupRadiusReply struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// UserGroupMemberQuery is the query computing the members of a dynamic
+// User Group
+type UserGroupMemberQuery struct {
+	// QueryType is always FilterQuery.
+	QueryType string `json:"queryType"`
+
+	// Filters all users have to match to be members of the group.
+	Filters []UserGroupMemberQueryFilter `json:"filters"`
+}
+
+// UserGroupMemberQueryFilter is a single filter of UserGroupMemberQuery
+type UserGroupMemberQueryFilter struct {
+	// Field is the user field to filter on, e.g. "department".
+	Field string `json:"field"`
+
+	// Operator is the comparison, e.g. "eq".
+	Operator string `json:"operator"`
+
+	// Value to compare the field with.
+	Value string `json:"value"`
+}
+
+// UserGroupSuggestion is a pending change of the members of a dynamic User
+// Group with review
+type UserGroupSuggestion struct {
+	// Object is the user to add to or remove from the group.
+	Object *jcapiv2.GraphObject `json:"object"`
+
+	// Op is either add or remove.
+	Op string `json:"op"`
+}
+
+// UserGroupSuggestionApproval is the request body applying the pending
+// suggestions of a User Group
+type UserGroupSuggestionApproval struct {
+	// UserIDs are the users whose suggested addition or removal is applied.
+	UserIDs []string `json:"user_ids"`
+}
+
+// SystemGroup is like jcapiv2.SystemGroup with the description and email
+type SystemGroup struct {
+	// ID uniquely identifies a System Group.
+	ID string `json:"id,omitempty"`
+
+	// Display name of a System Group.
+	Name string `json:"name,omitempty"`
+
+	// Description of a System Group.
+	Description string `json:"description,omitempty"`
+
+	// Email address of a System Group.
+	Email string `json:"email,omitempty"`
+}
+
+// SystemGroupData is like jcapiv2.SystemGroupData with the description and
+// email. Both are always sent, so that updates can clear them.
+type SystemGroupData struct {
+	// Display name of a System Group.
+	Name string `json:"name"`
+
+	// Description of a System Group.
+	Description string `json:"description"`
+
+	// Email address of a System Group.
+	Email string `json:"email"`
+}
+
+// SystemUser is like jcapiv1.Systemuserreturn with the fields the SDK
+// doesn't model, e.g. the restrictions set by directory integrations
+type SystemUser struct {
+	jcapiv1.Systemuserreturn
+
+	// RestrictedFields lists the fields owned by an external system
+	// (HRIS, Active Directory, ...) that must not be changed through the API.
+	RestrictedFields []SystemUserRestrictedField `json:"restrictedFields,omitempty"`
+
+	// State is the lifecycle state of the user: STAGED, ACTIVATED or SUSPENDED
+	State string `json:"state,omitempty"`
+}
+
+// SystemUserRestrictedField is a single entry of SystemUser.RestrictedFields
+type SystemUserRestrictedField struct {
+	// Field is the JumpCloud name of the restricted field, e.g. "firstname".
+	Field string `json:"field,omitempty"`
+
+	// ID of the integration owning the field.
+	ID string `json:"id,omitempty"`
+
+	// Type of the integration owning the field, e.g. "active_directory".
+	Type string `json:"type,omitempty"`
+}
+
+// GraphConnection is like jcapiv2.GraphConnection with the attributes of the
+// association, which the SDK doesn't return
+type GraphConnection struct {
+	From *jcapiv2.GraphObject `json:"from,omitempty"`
+	To   *jcapiv2.GraphObject `json:"to"`
+
+	// Attributes of the association, e.g. the sudo settings of a user
+	// bound to a system.
+	Attributes *jcapiv2.SystemGraphManagementReqAttributes `json:"attributes,omitempty"`
 }
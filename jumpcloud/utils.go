@@ -1,12 +1,19 @@
 package jumpcloud
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
 	"log"
+	"net/http"
+	"os"
 	"reflect"
 	"sort"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	jcapiv1 "github.com/TheJumpCloud/jcapi-go/v1"
@@ -40,6 +47,93 @@ func GetApplicationMetadataXml(orgId string, applicationId string, apiKey string
 	return string(resp.Body()), nil
 }
 
+// newAPIRequest builds a request against the JC's HTTP API directly, for the
+// endpoints and fields the jcapi-go SDK doesn't support
+func newAPIRequest(config *jcapiv2.Configuration, method, url string, body interface{}) (*http.Request, error) {
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		reader = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequest(method, url, reader)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("x-api-key", config.DefaultHeader["x-api-key"])
+	if config.DefaultHeader["x-org-id"] != "" {
+		req.Header.Add("x-org-id", config.DefaultHeader["x-org-id"])
+	}
+	req.Header.Add("Content-Type", "application/json")
+	req.Header.Add("Accept", "application/json")
+	return req, nil
+}
+
+// doAPIRequest sends a request to the JC's HTTP API and decodes the JSON
+// response into out, unless out is nil. Responses with a status of 300 or
+// above are returned as errors that include the status.
+func doAPIRequest(config *jcapiv2.Configuration, method, url string, body, out interface{}) error {
+	req, err := newAPIRequest(config, method, url, body)
+	if err != nil {
+		return err
+	}
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode >= 300 {
+		msg, _ := io.ReadAll(res.Body)
+		return fmt.Errorf("%s %s: %s %s", method, url, res.Status, msg)
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(res.Body).Decode(out)
+}
+
+// skipPlanChecksEnv disables the plan-time checks that query the JumpCloud API.
+// Terraform doesn't tell providers whether a plan refreshes, so this is meant
+// to be set together with `terraform plan -refresh=false`.
+const skipPlanChecksEnv = "JUMPCLOUD_SKIP_PLAN_CHECKS"
+
+// planChecksEnabled reports whether a CustomizeDiff function may call the
+// JumpCloud API. It returns false when the provider isn't configured yet, e.g.
+// because its credentials depend on values unknown at plan time.
+func planChecksEnabled(m interface{}) bool {
+	if skip, _ := strconv.ParseBool(os.Getenv(skipPlanChecksEnv)); skip {
+		return false
+	}
+	config, ok := m.(*jcapiv2.Configuration)
+	if !ok || config == nil {
+		return false
+	}
+	return config.DefaultHeader["x-api-key"] != ""
+}
+
+// isConfigured reports whether a top-level attribute is set in the configuration
+func isConfigured(d *schema.ResourceData, key string) bool {
+	config := d.GetRawConfig()
+	if config.IsNull() || !config.IsKnown() {
+		return false
+	}
+	return !config.GetAttr(key).IsNull()
+}
+
+// suppressIfConfigured hides the diff of an attribute while the given
+// alternative attribute is set in the configuration
+func suppressIfConfigured(alternative string) schema.SchemaDiffSuppressFunc {
+	return func(k, old, new string, d *schema.ResourceData) bool {
+		return isConfigured(d, alternative)
+	}
+}
+
 func stringInSlice(a string, list []string) bool {
 	for _, b := range list {
 		if b == a {
@@ -49,6 +143,15 @@ func stringInSlice(a string, list []string) bool {
 	return false
 }
 
+// expandStringSet converts a set of strings to a slice
+func expandStringSet(set *schema.Set) []string {
+	values := make([]string, 0, set.Len())
+	for _, v := range set.List() {
+		values = append(values, v.(string))
+	}
+	return values
+}
+
 func getUserGroupMemberIDs(client *jcapiv2.APIClient, groupID string) ([]string, error) {
 	var userIds []string
 	for i := 0; ; i++ {
@@ -77,103 +180,350 @@ func getUserGroupMemberIDs(client *jcapiv2.APIClient, groupID string) ([]string,
 	return userIds, nil
 }
 
-func userIDsToEmails(configv2 *jcapiv2.Configuration, userIDs []string) ([]string, error) {
-	emails := make([]string, len(userIDs))
+// userLookupChunkSize bounds the number of IDs or emails in the filter of a
+// single request, so that it stays within URL length limits
+const userLookupChunkSize = 50
 
-	if len(userIDs) == 0 {
-		return emails, nil
-	}
+// usersNotFoundError is returned by userEmailsToIDs for emails that don't
+// match any user
+type usersNotFoundError struct {
+	emails []string
+}
 
-	configv1 := convertV2toV1Config(configv2)
-	client := jcapiv1.NewAPIClient(configv1)
+func (e *usersNotFoundError) Error() string {
+	return fmt.Sprintf("users not found: %s", strings.Join(e.emails, ", "))
+}
 
-	for i := 0; ; i++ {
-		users, res, err := client.SystemusersApi.SystemusersList(context.TODO(), "", "", map[string]interface{}{
-			"filter": "_id:$in:" + strings.Join(userIDs[:], "|"),
-			"limit":  int32(100),
-			"skip":   int32(i * 100),
-			"fields": "email",
-			"sort":   "email",
+// userChunkLister lists the users whose field is one of the given values. On
+// failure, it returns the response of the failed request, if any.
+type userChunkLister func(field string, values []string) ([]jcapiv1.Systemuserreturn, *http.Response, error)
+
+// listUsersIn returns the userChunkLister backed by the v1 systemusers API,
+// loading only the given fields of the users
+func listUsersIn(configv2 *jcapiv2.Configuration, fields string) userChunkLister {
+	client := jcapiv1.NewAPIClient(convertV2toV1Config(configv2))
+	return func(field string, values []string) ([]jcapiv1.Systemuserreturn, *http.Response, error) {
+		var users []jcapiv1.Systemuserreturn
+		var res *http.Response
+		err := forEachPage(func(optionals map[string]interface{}) (int, error) {
+			optionals["filter"] = field + ":$in:" + strings.Join(values, "|")
+			optionals["fields"] = fields
+			page, pageRes, err := client.SystemusersApi.SystemusersList(context.TODO(), "", "", optionals)
+			if err != nil {
+				res = pageRes
+				return 0, err
+			}
+			users = append(users, page.Results...)
+			return len(page.Results), nil
 		})
+		return users, res, err
+	}
+}
 
-		if err != nil {
-			return nil, fmt.Errorf("error loading user emails from IDs: %s, i:%d, error:%s; response:%+v", userIDs, i, err, res)
+// userChunkResult is the result of looking up one chunk of users
+type userChunkResult struct {
+	users []jcapiv1.Systemuserreturn
+	err   error
+}
+
+// lookupUsers looks up the users whose field is one of the given values, in
+// chunks of userLookupChunkSize values resolved concurrently
+func lookupUsers(list userChunkLister, field string, values []string) ([]jcapiv1.Systemuserreturn, error) {
+	var chunks [][]string
+	for len(values) > 0 {
+		n := userLookupChunkSize
+		if len(values) < n {
+			n = len(values)
 		}
+		chunks = append(chunks, values[:n])
+		values = values[n:]
+	}
+	if len(chunks) == 0 {
+		return nil, nil
+	}
 
-		for j, result := range users.Results {
-			emails[j+(i*100)] = result.Email
+	// Determine number of workers
+	numWorkers := maxConcurrentGroupOps
+	if len(chunks) < numWorkers {
+		numWorkers = len(chunks)
+	}
+
+	chunkChan := make(chan []string, len(chunks))
+	resultChan := make(chan userChunkResult, len(chunks))
+
+	var wg sync.WaitGroup
+	for i := 0; i < numWorkers; i++ {
+		wg.Add(1)
+		go userLookupWorker(list, field, chunkChan, resultChan, &wg)
+	}
+	for _, chunk := range chunks {
+		chunkChan <- chunk
+	}
+	close(chunkChan)
+
+	wg.Wait()
+	close(resultChan)
+
+	var users []jcapiv1.Systemuserreturn
+	var errors []string
+	for res := range resultChan {
+		if res.err != nil {
+			errors = append(errors, res.err.Error())
+			continue
 		}
+		users = append(users, res.users...)
+	}
+	if len(errors) > 0 {
+		return nil, fmt.Errorf("errors looking up users by %s:\n%s", field, strings.Join(errors, "\n"))
+	}
+	return users, nil
+}
 
-		if len(users.Results) < 100 {
-			break
-		} else {
-			time.Sleep(100 * time.Millisecond)
+// userLookupWorker looks up the chunks from the channel with exponential
+// backoff retry
+func userLookupWorker(list userChunkLister, field string, chunks <-chan []string, results chan<- userChunkResult, wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	for chunk := range chunks {
+		var users []jcapiv1.Systemuserreturn
+		var res *http.Response
+		var err error
+		for attempt := 0; attempt < maxRetries; attempt++ {
+			if attempt > 0 {
+				backoff := time.Duration(baseBackoffMs*(1<<attempt)) * time.Millisecond
+				log.Printf("[DEBUG] userLookupWorker: Retry %d for %d users after %v", attempt, len(chunk), backoff)
+				time.Sleep(backoff)
+			}
+			users, res, err = list(field, chunk)
+			if err == nil || !isRetryableResponse(res) {
+				break
+			}
 		}
+		results <- userChunkResult{users: users, err: err}
+		time.Sleep(groupOpRateLimitMs * time.Millisecond)
 	}
+}
 
+// userIDsToEmails returns the emails of the users with the given IDs, keyed
+// by ID. IDs of users that don't exist anymore are left out.
+func userIDsToEmails(configv2 *jcapiv2.Configuration, userIDs []string) (map[string]string, error) {
+	users, err := userIDsToDetails(configv2, userIDs)
+	if err != nil {
+		return nil, err
+	}
+	emails := make(map[string]string, len(users))
+	for id, user := range users {
+		emails[id] = user.Email
+	}
 	return emails, nil
 }
 
-func userEmailsToIDs(configv2 *jcapiv2.Configuration, userEmailsInterface []interface{}) ([]string, error) {
-	userEmails := make([]string, len(userEmailsInterface))
-	for i, userEmail := range userEmailsInterface {
-		userEmails[i] = userEmail.(string)
+// memberEmails returns the emails of the given members in order, leaving out
+// users that don't exist anymore
+func memberEmails(configv2 *jcapiv2.Configuration, memberIDs []string) ([]string, error) {
+	emailsByID, err := userIDsToEmails(configv2, memberIDs)
+	if err != nil {
+		return nil, err
+	}
+	emails := make([]string, 0, len(memberIDs))
+	for _, id := range memberIDs {
+		if email, ok := emailsByID[id]; ok {
+			emails = append(emails, email)
+		}
 	}
+	return emails, nil
+}
 
-	ids := make([]string, len(userEmailsInterface))
+// userIDsToDetails returns the users with the given IDs, keyed by ID. Only
+// the e-mail address and username of the users are loaded.
+func userIDsToDetails(configv2 *jcapiv2.Configuration, userIDs []string) (map[string]jcapiv1.Systemuserreturn, error) {
+	return mapUsersByID(listUsersIn(configv2, "email username"), userIDs)
+}
 
-	if len(userEmails) == 0 {
-		return ids, nil
+func mapUsersByID(list userChunkLister, userIDs []string) (map[string]jcapiv1.Systemuserreturn, error) {
+	results, err := lookupUsers(list, "_id", userIDs)
+	if err != nil {
+		return nil, err
 	}
+	users := make(map[string]jcapiv1.Systemuserreturn, len(results))
+	for _, user := range results {
+		users[user.Id] = user
+	}
+	return users, nil
+}
 
-	configv1 := convertV2toV1Config(configv2)
-	client := jcapiv1.NewAPIClient(configv1)
+// userEmailsToIDs returns the IDs of the users with the given emails, keyed
+// by the emails as given. Emails are compared case-insensitively.
+func userEmailsToIDs(configv2 *jcapiv2.Configuration, userEmails []string) (map[string]string, error) {
+	return mapEmailsToIDs(listUsersIn(configv2, "email"), userEmails)
+}
 
-	for i := 0; ; i++ {
-		users, res, err := client.SystemusersApi.SystemusersList(context.TODO(), "", "", map[string]interface{}{
-			"filter": "email:$in:" + strings.Join(userEmails[:], "|"),
-			"limit":  int32(100),
-			"skip":   int32(i * 100),
-			"fields": "_id",
-			"sort":   "_id",
-		})
+func mapEmailsToIDs(list userChunkLister, userEmails []string) (map[string]string, error) {
+	// JumpCloud stores emails in lower case
+	lower := make([]string, 0, len(userEmails))
+	for _, email := range userEmails {
+		lower = append(lower, strings.ToLower(email))
+	}
+	results, err := lookupUsers(list, "email", lower)
+	if err != nil {
+		return nil, err
+	}
+	found := make(map[string]string, len(results))
+	for _, user := range results {
+		found[strings.ToLower(user.Email)] = user.Id
+	}
+
+	ids := make(map[string]string, len(userEmails))
+	var notFound []string
+	for _, email := range userEmails {
+		id, ok := found[strings.ToLower(email)]
+		if !ok {
+			notFound = append(notFound, email)
+			continue
+		}
+		ids[email] = id
+	}
+	if len(notFound) > 0 {
+		sort.Strings(notFound)
+		return nil, &usersNotFoundError{emails: notFound}
+	}
+	return ids, nil
+}
+
+// userAssociationLister lists one page of the direct associations of a user.
+// On failure, it returns the response of the failed request, if any.
+type userAssociationLister func(optionals map[string]interface{}) ([]GraphConnection, *http.Response, error)
 
+// sdkUserAssociations returns the userAssociationLister backed by
+// GraphUserAssociationsList
+func sdkUserAssociations(client *jcapiv2.APIClient, userID, targetType string) userAssociationLister {
+	return func(optionals map[string]interface{}) ([]GraphConnection, *http.Response, error) {
+		page, res, err := client.UsersApi.GraphUserAssociationsList(
+			context.TODO(), userID, "", headerAccept, []string{targetType}, optionals)
 		if err != nil {
-			return nil, fmt.Errorf("error loading user IDs from emails:%s; response = %+v", err, res)
+			return nil, res, fmt.Errorf("error getting %s associations for user id %s: %s", targetType, userID, err)
+		}
+		associations := make([]GraphConnection, len(page))
+		for i, v := range page {
+			associations[i] = GraphConnection{From: v.From, To: v.To}
+		}
+		return associations, res, nil
+	}
+}
+
+// httpUserAssociations returns the userAssociationLister consuming the JC's
+// HTTP API directly, as the SDK doesn't return the attributes of an association
+func httpUserAssociations(config *jcapiv2.Configuration, userID, targetType string) userAssociationLister {
+	return func(optionals map[string]interface{}) ([]GraphConnection, *http.Response, error) {
+		url := fmt.Sprintf("%s/users/%s/associations?targets=%s&limit=%d&skip=%d",
+			config.BasePath, userID, targetType, optionals["limit"], optionals["skip"])
+		req, err := newAPIRequest(config, http.MethodGet, url, nil)
+		if err != nil {
+			return nil, nil, err
 		}
 
-		for j, result := range users.Results {
-			ids[j+(i*100)] = result.Id
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return nil, nil, err
 		}
+		defer res.Body.Close()
 
-		if len(users.Results) < 100 {
+		if res.StatusCode >= 300 {
+			return nil, res, fmt.Errorf("error getting %s associations for user id %s: %s", targetType, userID, res.Status)
+		}
+		var page []GraphConnection
+		err = json.NewDecoder(res.Body).Decode(&page)
+		return page, res, err
+	}
+}
+
+// listUserAssociations pages through all associations the lister returns. On
+// failure, it returns the response of the failed request, if any.
+func listUserAssociations(list userAssociationLister) ([]GraphConnection, *http.Response, error) {
+	var associations []GraphConnection
+	var res *http.Response
+	err := forEachPage(func(optionals map[string]interface{}) (int, error) {
+		page, pageRes, err := list(optionals)
+		if err != nil {
+			res = pageRes
+			return 0, err
+		}
+		associations = append(associations, page...)
+		return len(page), nil
+	})
+	return associations, res, err
+}
+
+// listUserGroups returns all user groups of the organization
+func listUserGroups(client *jcapiv2.APIClient) ([]jcapiv2.UserGroup, error) {
+	var groups []jcapiv2.UserGroup
+	for i := 0; ; i++ {
+		page, res, err := client.UserGroupsApi.GroupsUserList(context.TODO(), "", headerAccept, map[string]interface{}{
+			"limit": int32(100),
+			"skip":  int32(i * 100),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error listing user groups: %s; response = %+v", err, res)
+		}
+
+		groups = append(groups, page...)
+
+		if len(page) < 100 {
 			break
 		} else {
 			time.Sleep(100 * time.Millisecond)
 		}
 	}
-
-	return ids, nil
+	return groups, nil
 }
 
-func manageGroupMember(client *jcapiv2.APIClient, d *schema.ResourceData, memberID string, action string) error {
-	payload := jcapiv2.UserGroupMembersReq{
-		Op:    action,
-		Type_: "user",
-		Id:    memberID,
+// closeMatches returns the candidates that look like a misspelling of name,
+// i.e. that differ only in case, contain each other or are within a small
+// edit distance
+func closeMatches(name string, candidates []string) []string {
+	lowerName := strings.ToLower(name)
+	maxDistance := len(name) / 3
+	if maxDistance < 2 {
+		maxDistance = 2
 	}
 
-	req := map[string]interface{}{
-		"body": payload,
+	var matches []string
+	for _, candidate := range candidates {
+		lowerCandidate := strings.ToLower(candidate)
+		if candidate == name {
+			continue
+		}
+		if lowerCandidate == lowerName ||
+			strings.Contains(lowerCandidate, lowerName) ||
+			strings.Contains(lowerName, lowerCandidate) ||
+			levenshtein(lowerName, lowerCandidate) <= maxDistance {
+			matches = append(matches, candidate)
+		}
 	}
+	sort.Strings(matches)
+	return matches
+}
 
-	res, err := client.UserGroupMembersMembershipApi.GraphUserGroupMembersPost(
-		context.TODO(), d.Id(), "", "", req)
-
-	if err != nil {
-		return fmt.Errorf("error managing group member, action: %s, member id:%s, error: %s; response = %+v", action, memberID, err, res)
+// levenshtein returns the edit distance between two strings
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
 	}
-	return nil
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
 }
 
 // getUserGroupIDs returns all group IDs that a user belongs to
@@ -184,26 +534,16 @@ func getUserGroupIDs(client *jcapiv2.APIClient, userID string) ([]string, error)
 	}
 
 	var groupIDs []string
-	maxIterations := 100 // Prevent infinite loops (supports up to 10,000 groups)
-
-	for i := 0; i < maxIterations; i++ {
-		optionals := map[string]interface{}{
-			"limit": int32(100),
-			"skip":  int32(i * 100),
-		}
-
-		log.Printf("[DEBUG] getUserGroupIDs: Fetching groups for user %s (page %d)", userID, i+1)
+	page := 0
+	err := forEachPage(func(optionals map[string]interface{}) (int, error) {
+		page++
+		log.Printf("[DEBUG] getUserGroupIDs: Fetching groups for user %s (page %d)", userID, page)
 
 		// Get all user group associations for this user
 		associations, res, err := client.UsersApi.GraphUserAssociationsList(
 			context.TODO(), userID, "user_group", "", []string{}, optionals)
 		if err != nil {
-			// Check if user doesn't exist or has been deleted
-			if strings.Contains(err.Error(), "not found") || strings.Contains(err.Error(), "404") {
-				log.Printf("[WARN] getUserGroupIDs: User %s not found, returning empty group list", userID)
-				return []string{}, nil
-			}
-			return nil, fmt.Errorf("error getting user groups for user id %s, error:%s; response = %+v", userID, err, res)
+			return 0, fmt.Errorf("error getting user groups for user id %s, error:%s; response = %+v", userID, err, res)
 		}
 
 		for _, assoc := range associations {
@@ -211,113 +551,67 @@ func getUserGroupIDs(client *jcapiv2.APIClient, userID string) ([]string, error)
 				groupIDs = append(groupIDs, assoc.To.Id)
 			}
 		}
-
-		if len(associations) < 100 {
-			break
-		} else {
-			time.Sleep(100 * time.Millisecond)
+		return len(associations), nil
+	})
+	if err != nil {
+		// Check if user doesn't exist or has been deleted
+		if strings.Contains(err.Error(), "not found") || strings.Contains(err.Error(), "404") {
+			log.Printf("[WARN] getUserGroupIDs: User %s not found, returning empty group list", userID)
+			return []string{}, nil
 		}
+		return nil, err
 	}
 
 	log.Printf("[DEBUG] getUserGroupIDs: Found %d groups for user %s", len(groupIDs), userID)
 	return groupIDs, nil
 }
 
-// syncUserGroups synchronizes a user's group memberships
-// It adds the user to new groups and removes from old groups
-func syncUserGroups(client *jcapiv2.APIClient, userID string, oldGroupIDs, newGroupIDs []string) error {
-	// Handle edge case: both lists are empty, nothing to do
-	if len(oldGroupIDs) == 0 && len(newGroupIDs) == 0 {
-		log.Println("[DEBUG] syncUserGroups: No groups to sync")
-		return nil
-	}
-
-	// Convert slices to maps for efficient lookup
-	oldGroups := make(map[string]bool)
-	for _, id := range oldGroupIDs {
-		if id != "" {
-			oldGroups[id] = true
-		}
-	}
+// pageDelay is the pause between two pages of forEachPage
+var pageDelay = 100 * time.Millisecond
 
-	newGroups := make(map[string]bool)
-	for _, id := range newGroupIDs {
-		if id != "" {
-			newGroups[id] = true
+// forEachPage calls fetch with the paging options of one page after the
+// other, until fetch returns less than a full page of records
+func forEachPage(fetch func(optionals map[string]interface{}) (int, error)) error {
+	for i := 0; ; i++ {
+		optionals := map[string]interface{}{
+			"limit": int32(100),
+			"skip":  int32(i * 100),
 		}
-	}
 
-	// Track errors but continue processing to sync as much as possible
-	var addErrors []string
-	var removeErrors []string
-	addCount := 0
-	removeCount := 0
-
-	// Add user to new groups
-	for groupID := range newGroups {
-		if !oldGroups[groupID] {
-			log.Printf("[DEBUG] syncUserGroups: Adding user %s to group %s", userID, groupID)
-			payload := jcapiv2.UserGroupMembersReq{
-				Op:    "add",
-				Type_: "user",
-				Id:    userID,
-			}
-			req := map[string]interface{}{
-				"body": payload,
-			}
-			res, err := client.UserGroupMembersMembershipApi.GraphUserGroupMembersPost(
-				context.TODO(), groupID, "", "", req)
-			if err != nil {
-				errMsg := fmt.Sprintf("error adding user %s to group %s: %s; response = %+v", userID, groupID, err, res)
-				log.Printf("[ERROR] %s", errMsg)
-				addErrors = append(addErrors, errMsg)
-			} else {
-				addCount++
-				// Add small delay to avoid rate limiting
-				if len(newGroups) > 10 {
-					time.Sleep(50 * time.Millisecond)
-				}
-			}
+		n, err := fetch(optionals)
+		if err != nil {
+			return err
 		}
-	}
 
-	// Remove user from old groups
-	for groupID := range oldGroups {
-		if !newGroups[groupID] {
-			log.Printf("[DEBUG] syncUserGroups: Removing user %s from group %s", userID, groupID)
-			payload := jcapiv2.UserGroupMembersReq{
-				Op:    "remove",
-				Type_: "user",
-				Id:    userID,
-			}
-			req := map[string]interface{}{
-				"body": payload,
-			}
-			res, err := client.UserGroupMembersMembershipApi.GraphUserGroupMembersPost(
-				context.TODO(), groupID, "", "", req)
-			if err != nil {
-				errMsg := fmt.Sprintf("error removing user %s from group %s: %s; response = %+v", userID, groupID, err, res)
-				log.Printf("[ERROR] %s", errMsg)
-				removeErrors = append(removeErrors, errMsg)
-			} else {
-				removeCount++
-				// Add small delay to avoid rate limiting
-				if len(oldGroups) > 10 {
-					time.Sleep(50 * time.Millisecond)
-				}
-			}
+		if n < 100 {
+			return nil
 		}
+		time.Sleep(pageDelay)
 	}
+}
 
-	log.Printf("[DEBUG] syncUserGroups: Successfully added user to %d groups, removed from %d groups", addCount, removeCount)
+// graphTraversal lists one page of the objects a graph object can reach
+type graphTraversal func(ctx context.Context, id string, contentType string, accept string,
+	optionals map[string]interface{}) ([]jcapiv2.GraphObjectWithPaths, *http.Response, error)
 
-	// Return combined error if any operations failed
-	if len(addErrors) > 0 || len(removeErrors) > 0 {
-		allErrors := append(addErrors, removeErrors...)
-		return fmt.Errorf("group synchronization partially failed:\n%s", strings.Join(allErrors, "\n"))
-	}
+// traverseGraph returns all objects the given traversal reaches from id
+func traverseGraph(traverse graphTraversal, id string) ([]jcapiv2.GraphObjectWithPaths, error) {
+	var objects []jcapiv2.GraphObjectWithPaths
+	err := forEachPage(func(optionals map[string]interface{}) (int, error) {
+		page, res, err := traverse(context.TODO(), id, "", headerAccept, optionals)
+		if err != nil {
+			return 0, fmt.Errorf("error traversing the graph from id %s, error:%s; response = %+v", id, err, res)
+		}
+		objects = append(objects, page...)
+		return len(page), nil
+	})
+	return objects, err
+}
 
-	return nil
+// hashStringIgnoringCase hashes the strings of a set so that values
+// differing only in case are the same element
+func hashStringIgnoringCase(v interface{}) int {
+	return schema.HashString(strings.ToLower(v.(string)))
 }
 
 // https://github.com/rootlyhq/terraform-provider-rootly/blob/99175a7ab4e154793ea8a8710d329a3f48eb0c90/tools/ignore_array_order.go#L12
@@ -0,0 +1,264 @@
+package jumpcloud
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	jcapiv1 "github.com/TheJumpCloud/jcapi-go/v1"
+	jcapiv2 "github.com/TheJumpCloud/jcapi-go/v2"
+)
+
+func TestPlanChecksEnabled(t *testing.T) {
+	configured := jcapiv2.NewConfiguration()
+	configured.AddDefaultHeader("x-api-key", "secret")
+
+	if planChecksEnabled(nil) {
+		t.Error("Expected plan checks to be disabled for an unconfigured provider")
+	}
+	if planChecksEnabled(jcapiv2.NewConfiguration()) {
+		t.Error("Expected plan checks to be disabled without an API key")
+	}
+	if !planChecksEnabled(configured) {
+		t.Error("Expected plan checks to be enabled for a configured provider")
+	}
+
+	t.Setenv(skipPlanChecksEnv, "true")
+	if planChecksEnabled(configured) {
+		t.Errorf("Expected plan checks to be disabled when %s is set", skipPlanChecksEnv)
+	}
+}
+
+func TestCloseMatches(t *testing.T) {
+	candidates := []string{"Developers", "developers-eu", "Admins", "Marketing", "devs"}
+
+	testCases := []struct {
+		name     string
+		expected []string
+	}{
+		{"developers", []string{"Developers", "developers-eu"}},
+		{"Admin", []string{"Admins"}},
+		{"Marketnig", []string{"Marketing"}},
+		{"finance", nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			matches := closeMatches(tc.name, candidates)
+			if len(matches) != len(tc.expected) {
+				t.Fatalf("Expected %v, got %v", tc.expected, matches)
+			}
+			for i := range matches {
+				if matches[i] != tc.expected[i] {
+					t.Errorf("Expected %v, got %v", tc.expected, matches)
+				}
+			}
+		})
+	}
+}
+
+func TestLevenshtein(t *testing.T) {
+	testCases := []struct {
+		a, b     string
+		distance int
+	}{
+		{"", "", 0},
+		{"abc", "", 3},
+		{"kitten", "sitting", 3},
+		{"admins", "admins", 0},
+	}
+
+	for _, tc := range testCases {
+		if d := levenshtein(tc.a, tc.b); d != tc.distance {
+			t.Errorf("levenshtein(%q, %q): expected %d, got %d", tc.a, tc.b, tc.distance, d)
+		}
+	}
+}
+
+// stubUserLister returns a userChunkLister over the given users, sorted by
+// email in reverse like the API may, recording the chunk sizes
+func stubUserLister(users []jcapiv1.Systemuserreturn, chunkSizes *[]int, mu *sync.Mutex) userChunkLister {
+	return func(field string, values []string) ([]jcapiv1.Systemuserreturn, *http.Response, error) {
+		mu.Lock()
+		*chunkSizes = append(*chunkSizes, len(values))
+		mu.Unlock()
+
+		wanted := make(map[string]bool, len(values))
+		for _, v := range values {
+			wanted[v] = true
+		}
+		var result []jcapiv1.Systemuserreturn
+		for i := len(users) - 1; i >= 0; i-- {
+			key := users[i].Id
+			if field == "email" {
+				key = users[i].Email
+			}
+			if wanted[key] {
+				result = append(result, users[i])
+			}
+		}
+		return result, nil, nil
+	}
+}
+
+func TestMapUsersByID(t *testing.T) {
+	var users []jcapiv1.Systemuserreturn
+	var ids []string
+	for i := 0; i < 120; i++ {
+		id := fmt.Sprintf("id%03d", i)
+		users = append(users, jcapiv1.Systemuserreturn{Id: id, Email: fmt.Sprintf("user%03d@testorg.com", 119-i)})
+		ids = append(ids, id)
+	}
+
+	var chunkSizes []int
+	var mu sync.Mutex
+	result, err := mapUsersByID(stubUserLister(users, &chunkSizes, &mu), append(ids, "deleted"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(chunkSizes) != 3 {
+		t.Errorf("Expected 3 chunks, got %v", chunkSizes)
+	}
+	for _, size := range chunkSizes {
+		if size > userLookupChunkSize {
+			t.Errorf("Expected chunks of at most %d values, got %d", userLookupChunkSize, size)
+		}
+	}
+	if len(result) != 120 {
+		t.Errorf("Expected 120 users, got %d", len(result))
+	}
+	for _, user := range users {
+		if result[user.Id].Email != user.Email {
+			t.Errorf("Expected %s for %s, got %s", user.Email, user.Id, result[user.Id].Email)
+		}
+	}
+	if _, ok := result["deleted"]; ok {
+		t.Error("Expected no entry for an unknown ID")
+	}
+}
+
+func TestMapEmailsToIDs(t *testing.T) {
+	users := []jcapiv1.Systemuserreturn{
+		{Id: "1", Email: "jane@testorg.com"},
+		{Id: "2", Email: "bob@testorg.com"},
+	}
+	var chunkSizes []int
+	var mu sync.Mutex
+
+	ids, err := mapEmailsToIDs(stubUserLister(users, &chunkSizes, &mu), []string{"Jane@testorg.com", "bob@testorg.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]string{"Jane@testorg.com": "1", "bob@testorg.com": "2"}
+	if !reflect.DeepEqual(ids, expected) {
+		t.Errorf("Expected %v, got %v", expected, ids)
+	}
+
+	_, err = mapEmailsToIDs(stubUserLister(users, &chunkSizes, &mu),
+		[]string{"jane@testorg.com", "zoe@testorg.com", "al@testorg.com"})
+	var notFound *usersNotFoundError
+	if !errors.As(err, &notFound) {
+		t.Fatalf("Expected a usersNotFoundError, got %v", err)
+	}
+	if !reflect.DeepEqual(notFound.emails, []string{"al@testorg.com", "zoe@testorg.com"}) {
+		t.Errorf("Expected the unknown emails, got %v", notFound.emails)
+	}
+}
+
+func TestLookupUsersRetries(t *testing.T) {
+	calls := 0
+	var mu sync.Mutex
+	list := func(field string, values []string) ([]jcapiv1.Systemuserreturn, *http.Response, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		calls++
+		if calls == 1 {
+			return nil, &http.Response{StatusCode: http.StatusTooManyRequests}, errors.New("429 Too Many Requests")
+		}
+		return []jcapiv1.Systemuserreturn{{Id: values[0]}}, nil, nil
+	}
+
+	users, err := lookupUsers(list, "_id", []string{"1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if calls != 2 || len(users) != 1 {
+		t.Errorf("Expected a successful retry, got %d calls and %v", calls, users)
+	}
+
+	failing := func(field string, values []string) ([]jcapiv1.Systemuserreturn, *http.Response, error) {
+		return nil, &http.Response{StatusCode: http.StatusInternalServerError}, errors.New("500 Internal Server Error")
+	}
+	if _, err := lookupUsers(failing, "_id", []string{"1"}); err == nil {
+		t.Error("Expected an error after exhausting the retries")
+	}
+
+	calls = 0
+	unauthorized := func(field string, values []string) ([]jcapiv1.Systemuserreturn, *http.Response, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		calls++
+		return nil, &http.Response{StatusCode: http.StatusUnauthorized}, errors.New("401 Unauthorized")
+	}
+	if _, err := lookupUsers(unauthorized, "_id", []string{"1"}); err == nil {
+		t.Error("Expected the error of a request that can't succeed")
+	}
+	if calls != 1 {
+		t.Errorf("Expected no retry of a 401 response, got %d calls", calls)
+	}
+}
+
+func TestListUserAssociations(t *testing.T) {
+	list := func(optionals map[string]interface{}) ([]GraphConnection, *http.Response, error) {
+		n := 100
+		if optionals["skip"].(int32) > 0 {
+			n = 5
+		}
+		page := make([]GraphConnection, n)
+		for i := range page {
+			page[i] = GraphConnection{To: &jcapiv2.GraphObject{Id: fmt.Sprint(optionals["skip"].(int32) + int32(i))}}
+		}
+		return page, &http.Response{StatusCode: http.StatusOK}, nil
+	}
+	associations, _, err := listUserAssociations(list)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(associations) != 105 || associations[104].To.Id != "104" {
+		t.Errorf("Expected all pages of associations, got %d", len(associations))
+	}
+
+	missing := func(optionals map[string]interface{}) ([]GraphConnection, *http.Response, error) {
+		return nil, &http.Response{StatusCode: http.StatusNotFound}, errors.New("404 Not Found")
+	}
+	_, res, err := listUserAssociations(missing)
+	if err == nil || res == nil || res.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected the response of the failed page, got %v (%v)", res, err)
+	}
+}
+
+func TestForEachPageReadsAllPages(t *testing.T) {
+	defer func(delay time.Duration) { pageDelay = delay }(pageDelay)
+	pageDelay = 0
+
+	// More than the 10,000 records the pager used to stop at
+	pages := 0
+	err := forEachPage(func(optionals map[string]interface{}) (int, error) {
+		pages++
+		if optionals["skip"].(int32) < 15000 {
+			return 100, nil
+		}
+		return 42, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pages != 151 {
+		t.Errorf("Expected all 151 pages to be read, got %d", pages)
+	}
+}